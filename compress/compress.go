@@ -0,0 +1,11 @@
+// Package compress provides compression formats for streams beyond the gzip
+// support built into the streams package.
+//
+// Each format implements streams.Compression, so it can decorate read and
+// write stream factories through streams.DecompressWith and
+// streams.CompressWith, or be detected by streams.Decompress.
+//
+// ZstdReader and ZstdWriter are the zstd counterparts of streams.Gzip and
+// streams.GzipWriter, taking the place of a streams.Zstd decorator. They live
+// in their own module so that importing streams does not pull in the codecs.
+package compress
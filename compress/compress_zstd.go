@@ -0,0 +1,59 @@
+package compress
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/sonirico/vago/streams"
+)
+
+// Zstd is the zstd streams.Compression. Levels follow the zstd command line,
+// from 1 (fastest) to 22 (best compression), and default to 3.
+var Zstd streams.Compression = zstdCompression{}
+
+type zstdCompression struct{}
+
+func (zstdCompression) Magic() []byte {
+	return []byte{0x28, 0xb5, 0x2f, 0xfd}
+}
+
+func (zstdCompression) NewReader(r io.Reader) (io.ReadCloser, error) {
+	dec, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("zstd: %w", err)
+	}
+
+	return dec.IOReadCloser(), nil
+}
+
+func (zstdCompression) NewWriter(w io.Writer, level *int) (streams.Compressor, error) {
+	encoderLevel := zstd.SpeedDefault
+	if level != nil {
+		encoderLevel = zstd.EncoderLevelFromZstd(*level)
+	}
+
+	enc, err := zstd.NewWriter(w, zstd.WithEncoderLevel(encoderLevel))
+	if err != nil {
+		return nil, fmt.Errorf("zstd: %w", err)
+	}
+
+	return enc, nil
+}
+
+// ZstdReader decorates a ReadStreamFactory so it reads zstd compressed input.
+// Closing the stream closes the decompressor and then the underlying reader.
+func ZstdReader[T any](factory streams.ReadStreamFactory[T]) streams.ReadStreamFactory[T] {
+	return streams.DecompressWith(Zstd, factory)
+}
+
+// ZstdWriter decorates a WriteStreamFactory so it writes zstd compressed
+// output. Closing the stream flushes and closes the compressor before the
+// underlying writer, so the last frame is never lost.
+func ZstdWriter[T any](
+	factory streams.WriteStreamFactory[T],
+	opts ...streams.CompressOpt,
+) streams.WriteStreamFactory[T] {
+	return streams.CompressWith(Zstd, factory, opts...)
+}
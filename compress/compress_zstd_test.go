@@ -0,0 +1,177 @@
+package compress
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sonirico/vago/streams"
+)
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+// readCloseRecorder tracks whether the underlying reader was closed.
+type readCloseRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *readCloseRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func linesFactory(rc io.ReadCloser) streams.ReadStream[string] {
+	return streams.Lines(rc)
+}
+
+func bytesWriterFactory(wc io.WriteCloser) streams.WriteStream[[]byte] {
+	return streams.Writer(wc)
+}
+
+func zstdBytes(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	w, err := zstd.NewWriter(&buf)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestZstd_Read(t *testing.T) {
+	const text = "first\nsecond\nthird\n"
+
+	tests := []struct {
+		name        string
+		factory     streams.ReadStreamFactory[string]
+		input       []byte
+		expected    []string
+		errContains string
+	}{
+		{
+			name:     "Zstd",
+			factory:  ZstdReader(linesFactory),
+			input:    zstdBytes(t, text),
+			expected: []string{"first", "second", "third"},
+		},
+		{
+			name:     "Detect zstd",
+			factory:  streams.Decompress(linesFactory, Zstd),
+			input:    zstdBytes(t, text),
+			expected: []string{"first", "second", "third"},
+		},
+		{
+			name:     "Detect plain",
+			factory:  streams.Decompress(linesFactory, Zstd),
+			input:    []byte(text),
+			expected: []string{"first", "second", "third"},
+		},
+		{
+			name:        "Zstd on plain input",
+			factory:     ZstdReader(linesFactory),
+			input:       []byte(text),
+			errContains: "invalid input",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := &readCloseRecorder{Reader: bytes.NewReader(tc.input)}
+			stream := tc.factory(src)
+
+			var got []string
+			for stream.Next() {
+				got = append(got, stream.Data())
+			}
+
+			if tc.errContains != "" {
+				assert.ErrorContains(t, stream.Err(), tc.errContains)
+			} else {
+				assert.NoError(t, stream.Err())
+				assert.Equal(t, tc.expected, got)
+			}
+
+			assert.NoError(t, stream.Close())
+			assert.True(t, src.closed)
+		})
+	}
+}
+
+func TestZstd_Write(t *testing.T) {
+	tests := []struct {
+		name    string
+		factory streams.WriteStreamFactory[[]byte]
+	}{
+		{
+			name:    "Default level",
+			factory: ZstdWriter(bytesWriterFactory),
+		},
+		{
+			name:    "Fastest",
+			factory: ZstdWriter(bytesWriterFactory, streams.WithCompressLevel(1)),
+		},
+		{
+			name:    "Best compression",
+			factory: ZstdWriter(bytesWriterFactory, streams.WithCompressLevel(22)),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			stream := tc.factory(nopWriteCloser{&buf})
+
+			_, err := streams.WriteAll(stream, [][]byte{[]byte("a\n"), []byte("b\n")})
+			require.NoError(t, err)
+			require.NoError(t, stream.Flush())
+			require.NoError(t, stream.Close())
+
+			result, err := streams.Consume(streams.Decompress(linesFactory, Zstd)(io.NopCloser(&buf)))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a", "b"}, result)
+		})
+	}
+}
+
+// ExampleZstdWriter demonstrates writing zstd compressed lines and detecting the format on read.
+func ExampleZstdWriter() {
+	var compressed bytes.Buffer
+
+	write := ZstdWriter(func(wc io.WriteCloser) streams.WriteStream[[]byte] {
+		return streams.Writer(wc)
+	})
+
+	w := write(nopWriteCloser{&compressed})
+	_, _ = w.Write([]byte("alpha\nbeta\n"))
+	_ = w.Close()
+
+	read := streams.Decompress(func(rc io.ReadCloser) streams.ReadStream[string] {
+		return streams.Lines(rc)
+	}, Zstd)
+
+	inputs := []io.Reader{&compressed, strings.NewReader("gamma\n")}
+	for _, input := range inputs {
+		stream := read(io.NopCloser(input))
+		for stream.Next() {
+			fmt.Println(stream.Data())
+		}
+		_ = stream.Close()
+	}
+	// Output:
+	// alpha
+	// beta
+	// gamma
+}
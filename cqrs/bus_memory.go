@@ -0,0 +1,514 @@
+package cqrs
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	maps "github.com/sonirico/stadio/ds/map"
+
+	"github.com/sonirico/vago/lol"
+	optslib "github.com/sonirico/vago/opts"
+	"github.com/sonirico/vago/rp"
+)
+
+const (
+	defaultMemoryBusPartitions = 4
+	defaultMemoryBusBufferSize = 1024
+)
+
+type (
+	memoryBusOpts struct {
+		async      bool
+		partitions int
+		bufferSize int
+	}
+
+	memoryPartition struct {
+		size int
+
+		mu    sync.Mutex
+		queue []rp.Msg
+
+		// queued is signalled whenever a message is queued, and room whenever
+		// one is taken or there is room left after queueing one.
+		queued chan struct{}
+		room   chan struct{}
+
+		// draining is held by whoever delivers the messages of the partition
+		// in synchronous mode.
+		draining sync.Mutex
+	}
+
+	// memoryHandlerKey marks the contexts handed to handlers.
+	memoryHandlerKey struct{}
+
+	memorySubscription struct {
+		ctx     context.Context
+		handler rp.ConsumerHandler
+		stop    chan struct{}
+		once    sync.Once
+		err     error
+	}
+)
+
+// MemoryBus is an in-process bus, meant for tests and single process
+// deployments. Its topic is split in partitions, each one a queue of messages.
+// Messages sharing a partition key always land on the same partition, so they
+// are handled in the order they were published.
+//
+// By default delivery is synchronous: publish handles the message, and
+// whatever its handler publishes in turn, in the calling goroutine before
+// returning. If another goroutine is delivering the partition at the time,
+// that goroutine handles the message instead and publish returns as soon as
+// it is queued. With MemoryBusWithAsyncDelivery publish always returns once
+// the message is queued and every partition is consumed by its own goroutine.
+//
+// Publish waits for room in the buffer of the partition, except when called
+// by a handler with the context it was given: messages published by handlers
+// are queued beyond the buffer size, so that handlers publishing to their own
+// partition, or to each other's, cannot deadlock.
+//
+// Messages published while nobody is subscribed are kept, up to the buffer
+// size of their partition, and delivered on subscription.
+type (
+	MemoryBus struct {
+		idx string
+
+		mtopic string
+		mcodec Codec
+
+		log lol.Logger
+
+		partitions []*memoryPartition
+		next       atomic.Uint32
+
+		mu  sync.Mutex
+		sub *memorySubscription
+
+		done      chan struct{}
+		closeOnce sync.Once
+
+		opts memoryBusOpts
+	}
+
+	MemoryEventBus struct {
+		*MemoryBus
+
+		eventHandlers maps.Map[string, EventHandler]
+		sagaHandlers  maps.Map[string, SagaHandler]
+	}
+
+	MemoryCommandBus struct {
+		*MemoryBus
+
+		handlers maps.Map[string, CommandHandler]
+	}
+)
+
+func (b *MemoryCommandBus) CommandHandler(h CommandHandler) CommandBus {
+	b.handlers.Set(hashKey(h), h)
+	return b
+}
+
+func (b *MemoryCommandBus) hasHandlers() bool {
+	return len(b.handlers.Keys()) > 0
+}
+
+func (b *MemoryCommandBus) commandHandler(h Handler) (CommandHandler, bool) {
+	return b.handlers.Get(hashKey(h))
+}
+
+func (b *MemoryEventBus) EventHandler(h EventHandler) EventBus {
+	b.eventHandlers.Set(hashKey(h), h)
+	return b
+}
+
+func (b *MemoryEventBus) SagaHandler(h SagaHandler) EventBus {
+	b.sagaHandlers.Set(hashKey(h), h)
+	return b
+}
+
+func (b *MemoryEventBus) eventHandler(h Handler) (EventHandler, bool) {
+	return b.eventHandlers.Get(hashKey(h))
+}
+
+func (b *MemoryEventBus) sagaHandler(h Handler) (SagaHandler, bool) {
+	return b.sagaHandlers.Get(hashKey(h))
+}
+
+func (b *MemoryEventBus) hasHandlers() bool {
+	return len(b.sagaHandlers.Keys()) > 0 || len(b.eventHandlers.Keys()) > 0
+}
+
+func newMemoryBus(
+	id string,
+	topic string,
+	log lol.Logger,
+	opts ...optslib.Configurator[MemoryBus],
+) *MemoryBus {
+	bus := &MemoryBus{
+		idx:    id,
+		mtopic: topic,
+		mcodec: NewJson(),
+		log:    log.WithFields(lol.Fields{"bus_id": id, "topic": topic}),
+		done:   make(chan struct{}),
+		opts: memoryBusOpts{
+			partitions: defaultMemoryBusPartitions,
+			bufferSize: defaultMemoryBusBufferSize,
+		},
+	}
+
+	optslib.ApplyAll(bus, opts...)
+
+	bus.partitions = make([]*memoryPartition, max(bus.opts.partitions, 1))
+	for i := range bus.partitions {
+		bus.partitions[i] = &memoryPartition{
+			size:   max(bus.opts.bufferSize, 1),
+			queued: make(chan struct{}, 1),
+			room:   make(chan struct{}, 1),
+		}
+	}
+
+	return bus
+}
+
+// NewMemoryCommandBus creates a new MemoryCommandBus. Unlike NewCommandBus it
+// cannot fail, as there is no broker to connect to. Messages are encoded as
+// JSON unless MemoryBusWithCodec is given.
+func NewMemoryCommandBus(
+	id string,
+	topic string,
+	log lol.Logger,
+	opts ...optslib.Configurator[MemoryBus],
+) *MemoryCommandBus {
+	return &MemoryCommandBus{
+		handlers: maps.NewConcurrent[string, CommandHandler](
+			maps.NewNative[string, CommandHandler](),
+		),
+		MemoryBus: newMemoryBus(id, topic, log, opts...),
+	}
+}
+
+// NewMemoryEventBus creates a new MemoryEventBus. Unlike NewEventBus it cannot
+// fail, as there is no broker to connect to. Messages are encoded as JSON
+// unless MemoryBusWithCodec is given.
+func NewMemoryEventBus(
+	id string,
+	topic string,
+	log lol.Logger,
+	opts ...optslib.Configurator[MemoryBus],
+) *MemoryEventBus {
+	return &MemoryEventBus{
+		eventHandlers: maps.NewConcurrent[string, EventHandler](
+			maps.NewNative[string, EventHandler](),
+		),
+		sagaHandlers: maps.NewConcurrent[string, SagaHandler](
+			maps.NewNative[string, SagaHandler](),
+		),
+		MemoryBus: newMemoryBus(id, topic, log, opts...),
+	}
+}
+
+func (b *MemoryBus) id() string { return b.idx }
+
+func (b *MemoryBus) topic() string { return b.mtopic }
+
+func (b *MemoryBus) codec() Codec { return b.mcodec }
+
+func (b *MemoryBus) publish(ctx context.Context, m rp.Msg) error {
+	if b.closed() {
+		return ErrBusClosed
+	}
+
+	if m.Topic == "" {
+		m.Topic = b.mtopic
+	}
+
+	if m.Ts.IsZero() {
+		m.Ts = time.Now().UTC()
+	}
+
+	m.Partition = b.partition(m.Key)
+	p := b.partitions[m.Partition]
+
+	bounded := ctx.Value(memoryHandlerKey{}) == nil
+	if err := p.push(ctx, b.done, m, bounded); err != nil {
+		return err
+	}
+
+	if !b.opts.async {
+		if sub := b.subscription(); sub != nil {
+			b.drain(sub, p)
+		}
+	}
+
+	return nil
+}
+
+// partition hashes keyed messages, like Redpanda does, and spreads the rest
+// in a round robin fashion.
+func (b *MemoryBus) partition(key []byte) int32 {
+	n := uint32(len(b.partitions))
+
+	if key == nil {
+		return int32((b.next.Add(1) - 1) % n)
+	}
+
+	h := fnv.New32a()
+	_, _ = h.Write(key)
+
+	return int32(h.Sum32() % n)
+}
+
+// subscribe delivers messages to handler until the context is done, the bus
+// is closed or handler returns an error, which is then returned. A bus has at
+// most one subscriber at a time.
+func (b *MemoryBus) subscribe(ctx context.Context, handler rp.ConsumerHandler) error {
+	if b.closed() {
+		return rp.ErrConsumerClosed
+	}
+
+	sub := &memorySubscription{
+		ctx:     ctx,
+		handler: handler,
+		stop:    make(chan struct{}),
+	}
+
+	b.mu.Lock()
+	if b.sub != nil {
+		b.mu.Unlock()
+		return ErrBusSubscribed
+	}
+	b.sub = sub
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		b.sub = nil
+		b.mu.Unlock()
+	}()
+
+	var wg sync.WaitGroup
+
+	for _, p := range b.partitions {
+		if b.opts.async {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				b.consume(sub, p)
+			}()
+		} else {
+			b.drain(sub, p)
+		}
+	}
+
+	select {
+	case <-sub.stop:
+	case <-ctx.Done():
+	case <-b.done:
+	}
+
+	sub.end(nil)
+	wg.Wait()
+
+	return sub.err
+}
+
+// consume handles the messages of a partition as they arrive, in async mode.
+func (b *MemoryBus) consume(sub *memorySubscription, p *memoryPartition) {
+	ctx := context.WithValue(sub.ctx, memoryHandlerKey{}, true)
+
+	for !b.stopped(sub) {
+		if m, ok := p.pop(); ok {
+			if err := sub.handler(ctx, m); err != nil {
+				sub.end(err)
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-sub.stop:
+			return
+		case <-sub.ctx.Done():
+			return
+		case <-b.done:
+			return
+		case <-p.queued:
+		}
+	}
+}
+
+// drain handles the messages queued in a partition, in sync mode. If the
+// partition is being drained already, its drainer takes care of them.
+func (b *MemoryBus) drain(sub *memorySubscription, p *memoryPartition) {
+	// Checking the queue again after unlocking catches messages queued by
+	// others while this goroutine was about to give up the partition.
+	for p.pending() && !b.stopped(sub) && p.draining.TryLock() {
+		b.drainLocked(sub, p)
+		p.draining.Unlock()
+	}
+}
+
+func (b *MemoryBus) drainLocked(sub *memorySubscription, p *memoryPartition) {
+	ctx := context.WithValue(sub.ctx, memoryHandlerKey{}, true)
+
+	for !b.stopped(sub) {
+		m, ok := p.pop()
+		if !ok {
+			return
+		}
+
+		if err := sub.handler(ctx, m); err != nil {
+			sub.end(err)
+			return
+		}
+	}
+}
+
+// push queues m, waiting for room in the buffer first if bounded.
+func (p *memoryPartition) push(
+	ctx context.Context,
+	done <-chan struct{},
+	m rp.Msg,
+	bounded bool,
+) error {
+	for {
+		p.mu.Lock()
+		if !bounded || len(p.queue) < p.size {
+			p.queue = append(p.queue, m)
+			room := len(p.queue) < p.size
+			p.mu.Unlock()
+
+			notify(p.queued)
+			// let the next publisher waiting for room in
+			if room {
+				notify(p.room)
+			}
+
+			return nil
+		}
+		p.mu.Unlock()
+
+		select {
+		case <-p.room:
+		case <-done:
+			return ErrBusClosed
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// pop takes the oldest message queued in the partition.
+func (p *memoryPartition) pop() (rp.Msg, bool) {
+	p.mu.Lock()
+	if len(p.queue) == 0 {
+		p.mu.Unlock()
+		return rp.Msg{}, false
+	}
+
+	m := p.queue[0]
+	p.queue[0] = rp.Msg{}
+	p.queue = p.queue[1:]
+	p.mu.Unlock()
+
+	notify(p.room)
+
+	return m, true
+}
+
+func (p *memoryPartition) pending() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.queue) > 0
+}
+
+// notify signals c without blocking. A signal already pending is enough.
+func notify(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
+
+func (b *MemoryBus) subscription() *memorySubscription {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.sub
+}
+
+func (b *MemoryBus) stopped(sub *memorySubscription) bool {
+	select {
+	case <-sub.stop:
+		return true
+	case <-sub.ctx.Done():
+		return true
+	case <-b.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (b *MemoryBus) closed() bool {
+	select {
+	case <-b.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// close stops the subscriber, if any, and drops every queued message.
+func (b *MemoryBus) close() {
+	b.closeOnce.Do(func() {
+		close(b.done)
+		b.log.Info("closed memory topic")
+	})
+}
+
+func (s *memorySubscription) end(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.stop)
+	})
+}
+
+// MemoryBusWithAsyncDelivery makes publish return once the message is queued,
+// leaving its handling to a goroutine per partition.
+func MemoryBusWithAsyncDelivery() optslib.Configurator[MemoryBus] {
+	return optslib.Fn[MemoryBus](func(bus *MemoryBus) {
+		bus.opts.async = true
+	})
+}
+
+func MemoryBusWithPartitions(n int) optslib.Configurator[MemoryBus] {
+	return optslib.Fn[MemoryBus](func(bus *MemoryBus) {
+		bus.opts.partitions = n
+	})
+}
+
+// MemoryBusWithBufferSize sets how many messages each partition holds before
+// publish blocks. Messages published by handlers are not bounded by it.
+func MemoryBusWithBufferSize(n int) optslib.Configurator[MemoryBus] {
+	return optslib.Fn[MemoryBus](func(bus *MemoryBus) {
+		bus.opts.bufferSize = n
+	})
+}
+
+func MemoryBusWithCodec(codec Codec) optslib.Configurator[MemoryBus] {
+	return optslib.Fn[MemoryBus](func(bus *MemoryBus) {
+		bus.mcodec = codec
+	})
+}
+
+var (
+	_ CommandBus = new(MemoryCommandBus)
+	_ EventBus   = new(MemoryEventBus)
+)
@@ -0,0 +1,666 @@
+package cqrs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sonirico/vago/fp"
+	"github.com/sonirico/vago/lol"
+	optslib "github.com/sonirico/vago/opts"
+	"github.com/sonirico/vago/ptr"
+	"github.com/sonirico/vago/rp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// NewZerolog sets zerolog globals, so tests share a single logger rather than
+// racing with the goroutines of previous ones.
+var newDiscardLogger = sync.OnceValue(func() lol.Logger {
+	return lol.NewZerolog(lol.WithWriter(io.Discard))
+})
+
+// subscribeMemory subscribes handler to b in the background and waits for the
+// subscription to be in place.
+func subscribeMemory(t *testing.T, ctx context.Context, b *MemoryBus, h rp.ConsumerHandler) <-chan error {
+	t.Helper()
+
+	errC := make(chan error, 1)
+	go func() { errC <- b.subscribe(ctx, h) }()
+
+	require.Eventually(t, func() bool {
+		return b.subscription() != nil
+	}, time.Second, time.Millisecond)
+
+	return errC
+}
+
+func waitErr(t *testing.T, errC <-chan error) error {
+	t.Helper()
+
+	select {
+	case err := <-errC:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("subscribe did not return")
+		return nil
+	}
+}
+
+func Test_MemoryBus_Container(t *testing.T) {
+	modes := map[string][]optslib.Configurator[MemoryBus]{
+		"sync":  nil,
+		"async": {MemoryBusWithAsyncDelivery()},
+	}
+
+	for name, opts := range modes {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			log := newDiscardLogger()
+			container := NewContainer(log, ContainerDisableErrorCapture(), ContainerDisableAPM())
+			defer container.Close()
+
+			var (
+				brokerEventBusID = "events-broker"
+				kycCommandBusID  = "commands-kyc"
+				kycEventBusID    = "events-kyc"
+			)
+
+			var (
+				closeC                   = make(chan struct{})
+				actualOrderCreated       orderCreatedEvent
+				actualLockBalanceCommand lockBalanceCommand
+				actualBalanceLockedEvent balanceLockedEvent
+			)
+
+			orderCreatedSaga := NewSagaHandler("0", "order", "created",
+				func(ctx context.Context, event Event, commander Commander) error {
+					if err := json.Unmarshal(event.Payload(), &actualOrderCreated); err != nil {
+						return err
+					}
+
+					commander.Command(ctx, kycCommandBusID, NewSimpleCommand("0", "applicant", "lock_balance",
+						lockBalanceCommand{Qty: actualOrderCreated.Qty}, nil))
+
+					return nil
+				},
+				nil,
+			)
+
+			lockBalanceCommandHandler := NewCommandHandler("0", "applicant", "lock_balance",
+				func(ctx context.Context, cmd Command, eventer Eventer) error {
+					if err := json.Unmarshal(cmd.Payload(), &actualLockBalanceCommand); err != nil {
+						return err
+					}
+
+					eventer.Event(ctx, kycEventBusID, NewSimpleEvent("0", "applicant", "balance_locked",
+						balanceLockedEvent{Locked: actualLockBalanceCommand.Qty, Free: 987}, nil))
+
+					return nil
+				},
+			)
+
+			balanceLockedEventHandler := NewEventHandler("0", "applicant", "balance_locked",
+				func(ctx context.Context, event Event) error {
+					if err := json.Unmarshal(event.Payload(), &actualBalanceLockedEvent); err != nil {
+						return err
+					}
+
+					close(closeC)
+
+					return nil
+				},
+			)
+
+			err := container.
+				EventBus(NewMemoryEventBus(kycEventBusID, "events.kyc", log, opts...).
+					EventHandler(balanceLockedEventHandler)).
+				EventBus(NewMemoryEventBus(brokerEventBusID, "events.broker", log, opts...).
+					SagaHandler(orderCreatedSaga)).
+				CommandBus(NewMemoryCommandBus(kycCommandBusID, "commands.kyc", log, opts...).
+					CommandHandler(lockBalanceCommandHandler)).
+				Start(ctx)
+			require.NoError(t, err)
+
+			err = container.Event(ctx, brokerEventBusID, NewSimpleEvent("0", "order", "created",
+				orderCreatedEvent{Status: "created", Qty: 123.456}, nil))
+			require.NoError(t, err)
+
+			select {
+			case <-closeC:
+			case <-time.After(time.Second):
+				t.Fatal("balance_locked event was not handled")
+			}
+
+			assert.Equal(t, orderCreatedEvent{Status: "created", Qty: 123.456}, actualOrderCreated)
+			assert.Equal(t, lockBalanceCommand{Qty: 123.456}, actualLockBalanceCommand)
+			assert.Equal(t, balanceLockedEvent{Locked: 123.456, Free: 987}, actualBalanceLockedEvent)
+		})
+	}
+}
+
+func Test_MemoryBus_SyncDelivery(t *testing.T) {
+	ctx := context.Background()
+	b := newMemoryBus("sync", "topic.sync", newDiscardLogger())
+	defer b.close()
+
+	var handled []string
+
+	errC := subscribeMemory(t, ctx, b, func(ctx context.Context, m rp.Msg) error {
+		handled = append(handled, string(m.Value))
+
+		// Publishing back to the same partition from the handler must not
+		// deadlock; the message is handled once this one is done.
+		if string(m.Value) == "a" {
+			return b.publish(ctx, rp.Msg{Key: []byte("k"), Value: []byte("a2")})
+		}
+
+		return nil
+	})
+
+	require.NoError(t, b.publish(ctx, rp.Msg{Key: []byte("k"), Value: []byte("a")}))
+	assert.Equal(t, []string{"a", "a2"}, handled, "handled before publish returns")
+
+	require.NoError(t, b.publish(ctx, rp.Msg{Value: []byte("b")}))
+	assert.Equal(t, []string{"a", "a2", "b"}, handled)
+
+	b.close()
+	assert.NoError(t, waitErr(t, errC))
+}
+
+func Test_MemoryBus_ReentrantPublishFullBuffer(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		t.Run(fmt.Sprintf("async=%t", async), func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			opts := []optslib.Configurator[MemoryBus]{
+				MemoryBusWithPartitions(1),
+				MemoryBusWithBufferSize(1),
+			}
+			if async {
+				opts = append(opts, MemoryBusWithAsyncDelivery())
+			}
+
+			b := newMemoryBus("reentrant", "topic.reentrant", newDiscardLogger(), opts...)
+			defer b.close()
+
+			var (
+				mu      sync.Mutex
+				handled []string
+				done    = make(chan struct{})
+			)
+
+			errC := subscribeMemory(t, ctx, b, func(ctx context.Context, m rp.Msg) error {
+				mu.Lock()
+				handled = append(handled, string(m.Value))
+				mu.Unlock()
+
+				switch string(m.Value) {
+				case "a":
+					// Far more than the buffer holds, none of them handled yet
+					for _, v := range []string{"a1", "a2", "a3"} {
+						if err := b.publish(ctx, rp.Msg{Value: []byte(v)}); err != nil {
+							return err
+						}
+					}
+				case "a3":
+					close(done)
+				}
+
+				return nil
+			})
+
+			require.NoError(t, b.publish(ctx, rp.Msg{Value: []byte("a")}))
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				t.Fatal("re-entrant publish deadlocked")
+			}
+
+			mu.Lock()
+			assert.Equal(t, []string{"a", "a1", "a2", "a3"}, handled)
+			mu.Unlock()
+
+			b.close()
+			assert.NoError(t, waitErr(t, errC))
+		})
+	}
+}
+
+func Test_MemoryBus_CrossPartitionPublishFullBuffers(t *testing.T) {
+	for _, async := range []bool{false, true} {
+		t.Run(fmt.Sprintf("async=%t", async), func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			opts := []optslib.Configurator[MemoryBus]{
+				MemoryBusWithPartitions(2),
+				MemoryBusWithBufferSize(1),
+			}
+			if async {
+				opts = append(opts, MemoryBusWithAsyncDelivery())
+			}
+
+			b := newMemoryBus("cross", "topic.cross", newDiscardLogger(), opts...)
+			defer b.close()
+
+			// Find a key for each partition
+			keys := make(map[int32][]byte)
+			for i := 0; len(keys) < 2; i++ {
+				key := []byte(fmt.Sprint(i))
+				if _, ok := keys[b.partition(key)]; !ok {
+					keys[b.partition(key)] = key
+				}
+			}
+
+			var (
+				mu      sync.Mutex
+				handled int
+				done    = make(chan struct{})
+			)
+
+			// Every message fans out to the other partition, beyond its
+			// buffer size, until the third generation
+			errC := subscribeMemory(t, ctx, b, func(ctx context.Context, m rp.Msg) error {
+				mu.Lock()
+				handled++
+				if handled == 1+3+9 {
+					close(done)
+				}
+				mu.Unlock()
+
+				gen := int(m.Value[0] - '0')
+				if gen == 2 {
+					return nil
+				}
+
+				for range 3 {
+					next := rp.Msg{Key: keys[1-m.Partition], Value: []byte(fmt.Sprint(gen + 1))}
+					if err := b.publish(ctx, next); err != nil {
+						return err
+					}
+				}
+
+				return nil
+			})
+
+			require.NoError(t, b.publish(ctx, rp.Msg{Key: keys[0], Value: []byte("0")}))
+
+			select {
+			case <-done:
+			case <-ctx.Done():
+				t.Fatal("handlers publishing to each other deadlocked")
+			}
+
+			b.close()
+			assert.NoError(t, waitErr(t, errC))
+		})
+	}
+}
+
+func Test_MemoryBus_SyncPublishWhileDraining(t *testing.T) {
+	ctx := context.Background()
+	b := newMemoryBus("draining", "topic.draining", newDiscardLogger(), MemoryBusWithPartitions(1))
+	defer b.close()
+
+	var (
+		mu      sync.Mutex
+		handled []string
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+
+	errC := subscribeMemory(t, ctx, b, func(_ context.Context, m rp.Msg) error {
+		if string(m.Value) == "a" {
+			close(started)
+			<-release
+		}
+
+		mu.Lock()
+		handled = append(handled, string(m.Value))
+		mu.Unlock()
+
+		return nil
+	})
+
+	published := make(chan error)
+	go func() { published <- b.publish(ctx, rp.Msg{Value: []byte("a")}) }()
+	<-started
+
+	// The goroutine delivering "a" delivers "b" as well, so publish returns
+	// without waiting for it
+	require.NoError(t, b.publish(ctx, rp.Msg{Value: []byte("b")}))
+
+	mu.Lock()
+	assert.Empty(t, handled)
+	mu.Unlock()
+
+	close(release)
+	require.NoError(t, <-published)
+
+	mu.Lock()
+	assert.Equal(t, []string{"a", "b"}, handled)
+	mu.Unlock()
+
+	b.close()
+	assert.NoError(t, waitErr(t, errC))
+}
+
+func Test_MemoryBus_PublishBeforeSubscribe(t *testing.T) {
+	ctx := context.Background()
+	b := newMemoryBus("pending", "topic.pending", newDiscardLogger(), MemoryBusWithPartitions(1))
+	defer b.close()
+
+	for i := range 3 {
+		require.NoError(t, b.publish(ctx, rp.Msg{Value: []byte(fmt.Sprint(i))}))
+	}
+
+	var (
+		mu      sync.Mutex
+		handled []string
+	)
+
+	subscribeMemory(t, ctx, b, func(_ context.Context, m rp.Msg) error {
+		mu.Lock()
+		defer mu.Unlock()
+		handled = append(handled, string(m.Value))
+		return nil
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"0", "1", "2"}, handled)
+}
+
+func Test_MemoryBus_PartitionKeyOrdering(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := newMemoryBus("async", "topic.async", newDiscardLogger(),
+		MemoryBusWithAsyncDelivery(),
+		MemoryBusWithPartitions(4),
+	)
+	defer b.close()
+
+	const (
+		keys     = 8
+		messages = 50
+	)
+
+	var (
+		mu         sync.Mutex
+		received   = make(map[string][]int)
+		partitions = make(map[string]map[int32]struct{})
+		wg         sync.WaitGroup
+	)
+
+	wg.Add(keys * messages)
+
+	subscribeMemory(t, ctx, b, func(_ context.Context, m rp.Msg) error {
+		defer wg.Done()
+
+		var seq int
+		if err := json.Unmarshal(m.Value, &seq); err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		k := string(m.Key)
+		received[k] = append(received[k], seq)
+		if partitions[k] == nil {
+			partitions[k] = make(map[int32]struct{})
+		}
+		partitions[k][m.Partition] = struct{}{}
+
+		return nil
+	})
+
+	for seq := range messages {
+		for k := range keys {
+			value, _ := json.Marshal(seq)
+			key := partitionKey(NewSimpleCommand("0", "balance", "lock", nil, ptr.Ptr(fmt.Sprint("user-", k))))
+			require.NoError(t, b.publish(ctx, rp.Msg{Key: key, Value: value}))
+		}
+	}
+
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	require.Len(t, received, keys)
+	for k, seqs := range received {
+		assert.Len(t, seqs, messages, k)
+		assert.IsIncreasing(t, seqs, k)
+		assert.Len(t, partitions[k], 1, "%s landed on a single partition", k)
+	}
+}
+
+func Test_MemoryBus_HandlerErrorStopsSubscription(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	for name, opts := range map[string][]optslib.Configurator[MemoryBus]{
+		"sync":  nil,
+		"async": {MemoryBusWithAsyncDelivery()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			b := newMemoryBus(name, "topic."+name, newDiscardLogger(), opts...)
+			defer b.close()
+
+			errC := subscribeMemory(t, ctx, b, func(context.Context, rp.Msg) error {
+				return boom
+			})
+
+			require.NoError(t, b.publish(ctx, rp.Msg{Value: []byte("x")}))
+			assert.ErrorIs(t, waitErr(t, errC), boom)
+
+			// The bus can be subscribed to again
+			handled := make(chan string, 1)
+			subscribeMemory(t, ctx, b, func(_ context.Context, m rp.Msg) error {
+				handled <- string(m.Value)
+				return nil
+			})
+
+			require.NoError(t, b.publish(ctx, rp.Msg{Value: []byte("y")}))
+			select {
+			case v := <-handled:
+				assert.Equal(t, "y", v)
+			case <-time.After(time.Second):
+				t.Fatal("message was not handled")
+			}
+		})
+	}
+}
+
+func Test_MemoryBus_Subscribe(t *testing.T) {
+	t.Run("A single subscriber at a time", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		b := newMemoryBus("single", "topic.single", newDiscardLogger())
+		defer b.close()
+
+		errC := subscribeMemory(t, ctx, b, func(context.Context, rp.Msg) error { return nil })
+		assert.ErrorIs(t, b.subscribe(ctx, func(context.Context, rp.Msg) error { return nil }), ErrBusSubscribed)
+
+		cancel()
+		assert.NoError(t, waitErr(t, errC))
+	})
+
+	t.Run("Closed bus", func(t *testing.T) {
+		ctx := context.Background()
+		b := newMemoryBus("closed", "topic.closed", newDiscardLogger())
+		b.close()
+		b.close()
+
+		assert.ErrorIs(t, b.publish(ctx, rp.Msg{}), ErrBusClosed)
+		assert.ErrorIs(t, b.subscribe(ctx, func(context.Context, rp.Msg) error { return nil }), rp.ErrConsumerClosed)
+	})
+
+	t.Run("Publish blocks while the buffer is full", func(t *testing.T) {
+		b := newMemoryBus("full", "topic.full", newDiscardLogger(),
+			MemoryBusWithPartitions(1),
+			MemoryBusWithBufferSize(1),
+		)
+		defer b.close()
+
+		require.NoError(t, b.publish(context.Background(), rp.Msg{}))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.ErrorIs(t, b.publish(ctx, rp.Msg{}), context.DeadlineExceeded)
+	})
+}
+
+func Test_Container_ErrorProducer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := newDiscardLogger()
+	producer := rp.NewMemoryProducer()
+
+	container := NewContainer(log, ContainerWithErrorProducer(producer), ContainerDisableAPM())
+	defer container.Close()
+
+	bus := NewMemoryCommandBus("commands", "commands.test", log)
+	bus.CommandHandler(NewCommandHandler("0", "order", "create",
+		func(context.Context, Command, Eventer) error {
+			return errors.New("out of stock")
+		},
+	))
+
+	require.NoError(t, container.CommandBus(bus).Start(ctx))
+	require.Eventually(t, func() bool {
+		return bus.subscription() != nil
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, container.Command(ctx, "commands", NewSimpleCommand("0", "order", "create", nil, nil)))
+
+	msgs := producer.Data()
+	require.Len(t, msgs, 1)
+	assert.Equal(t, TopicErrors, msgs[0].Topic)
+
+	var captured struct {
+		Kind    string `json:"kind"`
+		Handler struct {
+			Resource string `json:"resource"`
+			Action   string `json:"action"`
+		} `json:"handler"`
+	}
+	require.NoError(t, json.Unmarshal(msgs[0].Value, &captured))
+	assert.Equal(t, KindCommands, captured.Kind)
+	assert.Equal(t, "order", captured.Handler.Resource)
+	assert.Equal(t, "create", captured.Handler.Action)
+}
+
+func Test_Container_UndecodableMsg(t *testing.T) {
+	ctx := context.Background()
+
+	log := newDiscardLogger()
+	producer := rp.NewMemoryProducer()
+
+	container := NewContainer(log, ContainerWithErrorProducer(producer), ContainerDisableAPM())
+	defer container.Close()
+
+	bus := NewMemoryCommandBus("commands", "commands.undecodable", log)
+	bus.CommandHandler(NewCommandHandler("0", "order", "create",
+		func(context.Context, Command, Eventer) error { return nil },
+	))
+
+	errC := make(chan error, 1)
+	go func() { errC <- container.commandBusSubscribe(ctx, log, bus) }()
+	require.Eventually(t, func() bool {
+		return bus.subscription() != nil
+	}, time.Second, time.Millisecond)
+
+	require.NoError(t, bus.publish(ctx, rp.Msg{Value: []byte("not json")}))
+
+	// Unless ContainerSkipUndecodable is set, consumption stops
+	assert.ErrorIs(t, waitErr(t, errC), ErrSubscribeNonRecoverable)
+	assert.Empty(t, producer.Data())
+}
+
+func Test_Container_CapturedErrorShape(t *testing.T) {
+	producer := rp.NewMemoryProducer()
+	container := NewContainer(newDiscardLogger(), ContainerWithErrorProducer(producer), ContainerDisableAPM())
+
+	ctx := context.Background()
+	boom := errors.New("boom")
+
+	container.logError(ctx, KindCommands, boom, rp.Msg{Value: []byte(`{"id":1}`)}, fp.None[Handler]())
+
+	msgs := producer.Data()
+	require.Len(t, msgs, 1)
+
+	var captured struct {
+		Error   json.RawMessage `json:"error"`
+		Payload struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"payload"`
+	}
+	require.NoError(t, json.Unmarshal(msgs[0].Value, &captured))
+
+	// The wire format is shared with every consumer of the errors topic: the
+	// error is marshaled as is and the value is embedded as raw JSON
+	assert.JSONEq(t, `{}`, string(captured.Error))
+	assert.JSONEq(t, `{"id":1}`, string(captured.Payload.Value))
+
+	// Values that are not JSON cannot be embedded, so they are only logged
+	container.logError(ctx, KindCommands, boom, rp.Msg{Value: []byte("not json")}, fp.None[Handler]())
+	assert.Len(t, producer.Data(), 1)
+}
+
+// ExampleNewMemoryCommandBus demonstrates running a container without any
+// broker, e.g. in tests or single process deployments.
+func ExampleNewMemoryCommandBus() {
+	ctx := context.Background()
+	log := lol.ZeroTestLogger
+
+	type placeOrder struct {
+		Symbol string `json:"symbol"`
+	}
+
+	container := NewContainer(log, ContainerDisableErrorCapture(), ContainerDisableAPM())
+	defer container.Close()
+
+	commands := NewMemoryCommandBus("commands", "commands.orders", log)
+	commands.CommandHandler(NewTypedCommandHandler("0", "order", "place",
+		func(ctx context.Context, _ Command, order placeOrder, eventer Eventer) error {
+			fmt.Println("placing", order.Symbol)
+			eventer.Event(ctx, "events", NewSimpleEvent("0", "order", "placed", order, nil))
+			return nil
+		},
+	))
+
+	events := NewMemoryEventBus("events", "events.orders", log)
+	events.EventHandler(NewTypedEventHandler("0", "order", "placed",
+		func(_ context.Context, _ Event, order placeOrder) error {
+			fmt.Println("placed", order.Symbol)
+			return nil
+		},
+	))
+
+	_ = container.CommandBus(commands).EventBus(events).Start(ctx)
+
+	// Wait for the container to subscribe
+	for commands.subscription() == nil || events.subscription() == nil {
+		time.Sleep(time.Millisecond)
+	}
+
+	// Delivery is synchronous: the command, and the event it leads to, are
+	// handled before Command returns
+	_ = container.Command(ctx, "commands", NewSimpleCommand("0", "order", "place", placeOrder{"BTC"}, nil))
+	// Output:
+	// placing BTC
+	// placed BTC
+}
@@ -26,7 +26,8 @@ import (
 type Container struct {
 	log lol.Logger
 
-	producer rp.Producer
+	producer     rp.Producer
+	producerOnce sync.Once
 
 	commandBuses maps.Map[string, CommandBus]
 	eventBuses   maps.Map[string, EventBus]
@@ -36,6 +37,7 @@ type Container struct {
 	mustProcessOrFail    bool
 	errorCaptureDisabled bool
 	apmDisabled          bool
+	skipUndecodable      bool
 
 	closeC    chan error
 	closeOnce sync.Once
@@ -51,61 +53,6 @@ func NewContainer(log lol.Logger, opts ...optslib.Configurator[Container]) *Cont
 
 	optslib.ApplyAll(container, opts...)
 
-	if !container.errorCaptureDisabled {
-		var err error
-		container.producer, err = rp.NewProducer(
-			context.Background(),
-			rp.ProducerConfig{
-				FlushTimeout: ent.Duration(
-					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_FLUSH_TIMEOUT",
-					time.Second*5,
-				),
-				Timeout: ent.Duration(
-					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_TIMEOUT",
-					time.Second*5,
-				),
-				ProduceRequestTimeout: ent.Duration(
-					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_PRODUCE_REQUEST_TIMEOUT",
-					time.Second*5,
-				),
-				ConnIdleTimeout: ent.Duration(
-					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_CONN_IDLE_TIMEOUT",
-					time.Second*5,
-				),
-				RequestTimeoutOverhead: ent.Duration(
-					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_REQUEST_TIMEOUT_OVERHEAD",
-					time.Second*5,
-				),
-				RecordDeliveryTimeout: ent.Duration(
-					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_RECORD_DELIVERY_TIMEOUT",
-					time.Second*5,
-				),
-				SessionTimeout: ent.Duration(
-					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_SESSION_TIMEOUT",
-					time.Second*5,
-				),
-				MaxBufferedRecords: ent.Int(
-					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_MAX_BUFFERED_RECORDS",
-					10_000,
-				),
-				Brokers: ent.SliceStr(
-					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_BROKERS",
-					[]string{"redpanda-cluster:9092"},
-				),
-				WithLogger:  false,
-				WithNoAPM:   true,
-				Compression: 0,
-				App:         "vago/cqrs",
-				Version:     "0.1.0",
-			},
-			log.WithField("producer", "errors"),
-		)
-
-		if err != nil {
-			log.Errorf("error creating Redpanda producer for errors: %v", err)
-		}
-	}
-
 	return container
 }
 
@@ -207,10 +154,18 @@ func (c *Container) commandBusSubscribe(ctx context.Context, l lol.Logger, bus C
 			recordKey:       m.Key,
 			recordPartition: m.Partition,
 			recordTs:        m.Ts,
+			codec:           bus.codec(),
 		}
 		if err := bus.codec().Decode(m.Value, &recv); err != nil {
-			//stop consuming
-			return fmt.Errorf("%w: unable to decode msg: %v", ErrSubscribeNonRecoverable, err)
+			if !c.skipUndecodable {
+				//stop consuming
+				return fmt.Errorf("%w: unable to decode msg: %v", ErrSubscribeNonRecoverable, err)
+			}
+
+			err = fmt.Errorf("%w: %v", ErrDecodeMsg, err)
+			c.logError(ctx, KindCommands, err, m, fp.None[Handler]())
+			l.Errorln(err)
+			return nil
 		}
 
 		k := hashKey(recv)
@@ -255,11 +210,12 @@ func (c *Container) commandBusSubscribe(ctx context.Context, l lol.Logger, bus C
 		w := &containerWrapper{Container: c}
 
 		if err := cmdHandler.Handle(ctx, recv.Command(), w); err != nil {
+			payloadErr := isPayloadError(err)
 			err = fmt.Errorf("%w: %v", ErrHandleCommand, err)
 
 			c.logError(ctx, KindCommands, err, m, fp.Some[Handler](cmdHandler))
 
-			if c.mustProcessOrFail {
+			if c.mustProcessOrFail && !payloadErr {
 				c.close(err)
 				return err
 			}
@@ -289,11 +245,19 @@ func (c *Container) eventBusSubscribe(ctx context.Context, l lol.Logger, bus Eve
 			recordKey:       m.Key,
 			recordPartition: m.Partition,
 			recordTs:        m.Ts,
+			codec:           bus.codec(),
 		}
 
 		if err := bus.codec().Decode(m.Value, &msg); err != nil {
-			//stop consuming
-			return fmt.Errorf("%w: unable to decode msg: %v", ErrSubscribeNonRecoverable, err)
+			if !c.skipUndecodable {
+				//stop consuming
+				return fmt.Errorf("%w: unable to decode msg: %v", ErrSubscribeNonRecoverable, err)
+			}
+
+			err = fmt.Errorf("%w: %v", ErrDecodeMsg, err)
+			c.logError(ctx, KindEvents, err, m, fp.None[Handler]())
+			l.Errorln(err)
+			return nil
 		}
 
 		l.Debugf("[e][<] %v", msg)
@@ -373,7 +337,7 @@ func (c *Container) eventBusSubscribe(ctx context.Context, l lol.Logger, bus Eve
 			err := fmt.Errorf("%w: event err = '%s', saga err = '%s'",
 				ErrHandleEvent, eventError, sagaError)
 
-			if c.mustProcessOrFail {
+			if c.mustProcessOrFail && !isPayloadError(errEvent, errSaga) {
 				c.close(err)
 				return err
 			}
@@ -474,6 +438,72 @@ func (c *Container) send(ctx context.Context, kind string, msg sendMsg, bus bus)
 	return nil
 }
 
+// errorProducer returns the producer errors are captured with. Unless one was
+// given with ContainerWithErrorProducer, it is created on first use, so that
+// containers whose handlers never fail do not need a broker.
+func (c *Container) errorProducer() rp.Producer {
+	c.producerOnce.Do(func() {
+		if c.producer != nil {
+			return
+		}
+
+		var err error
+		c.producer, err = rp.NewProducer(
+			context.Background(),
+			rp.ProducerConfig{
+				FlushTimeout: ent.Duration(
+					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_FLUSH_TIMEOUT",
+					time.Second*5,
+				),
+				Timeout: ent.Duration(
+					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_TIMEOUT",
+					time.Second*5,
+				),
+				ProduceRequestTimeout: ent.Duration(
+					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_PRODUCE_REQUEST_TIMEOUT",
+					time.Second*5,
+				),
+				ConnIdleTimeout: ent.Duration(
+					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_CONN_IDLE_TIMEOUT",
+					time.Second*5,
+				),
+				RequestTimeoutOverhead: ent.Duration(
+					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_REQUEST_TIMEOUT_OVERHEAD",
+					time.Second*5,
+				),
+				RecordDeliveryTimeout: ent.Duration(
+					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_RECORD_DELIVERY_TIMEOUT",
+					time.Second*5,
+				),
+				SessionTimeout: ent.Duration(
+					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_SESSION_TIMEOUT",
+					time.Second*5,
+				),
+				MaxBufferedRecords: ent.Int(
+					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_MAX_BUFFERED_RECORDS",
+					10_000,
+				),
+				Brokers: ent.SliceStr(
+					"VAGO_CQRS_ERROR_PRODUCER_Redpanda_BROKERS",
+					[]string{"redpanda-cluster:9092"},
+				),
+				WithLogger:  false,
+				WithNoAPM:   true,
+				Compression: 0,
+				App:         "vago/cqrs",
+				Version:     "0.1.0",
+			},
+			c.log.WithField("producer", "errors"),
+		)
+
+		if err != nil {
+			c.log.Errorf("error creating Redpanda producer for errors: %v", err)
+		}
+	})
+
+	return c.producer
+}
+
 func (c *Container) logError(
 	ctx context.Context,
 	kind string,
@@ -544,7 +574,13 @@ func (c *Container) logError(
 		return
 	}
 
-	errProduce := c.producer.Publish(ctx, rp.Msg{
+	producer := c.errorProducer()
+	if producer == nil {
+		c.log.Errorf("unable to publish error log '%v': no error producer", err)
+		return
+	}
+
+	errProduce := producer.Publish(ctx, rp.Msg{
 		Topic: TopicErrors,
 		Value: value,
 		Ts:    now,
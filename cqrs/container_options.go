@@ -1,6 +1,9 @@
 package cqrs
 
-import "github.com/sonirico/vago/opts"
+import (
+	"github.com/sonirico/vago/opts"
+	"github.com/sonirico/vago/rp"
+)
 
 func ContainerMustProcessOrFail() opts.Configurator[Container] {
 	return opts.Fn[Container](func(c *Container) {
@@ -26,8 +29,27 @@ func ContainerDisableErrorCapture() opts.Configurator[Container] {
 	})
 }
 
+// ContainerSkipUndecodable captures messages whose envelope cannot be decoded
+// as ErrDecodeMsg and carries on with the next one. By default they stop
+// consumption with ErrSubscribeNonRecoverable. Values that are not JSON at all
+// cannot be captured in the errors topic and are only logged.
+func ContainerSkipUndecodable() opts.Configurator[Container] {
+	return opts.Fn[Container](func(c *Container) {
+		c.skipUndecodable = true
+	})
+}
+
 func ContainerDisableAPM() opts.Configurator[Container] {
 	return opts.Fn[Container](func(c *Container) {
 		c.apmDisabled = true
 	})
 }
+
+// ContainerWithErrorProducer sets the producer handler errors are captured
+// with, instead of the Redpanda producer created on the first error. Passing
+// an rp.MemoryProducer keeps the container free of any broker.
+func ContainerWithErrorProducer(p rp.Producer) opts.Configurator[Container] {
+	return opts.Fn[Container](func(c *Container) {
+		c.producer = p
+	})
+}
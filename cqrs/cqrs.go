@@ -1,3 +1,6 @@
+// Package cqrs provides command and event buses with typed handlers and sagas,
+// wired together by a Container. Buses are backed by Redpanda, or kept in
+// memory for tests and single process deployments.
 package cqrs
 
 import "context"
@@ -4,6 +4,8 @@ import "errors"
 
 var (
 	ErrBusNotFound             = errors.New("bus not found")
+	ErrBusClosed               = errors.New("bus is closed")
+	ErrBusSubscribed           = errors.New("bus already has a subscriber")
 	ErrHandleCommand           = errors.New("error handling command")
 	ErrHandleEvent             = errors.New("error handling event")
 	ErrSubscribeNonRecoverable = errors.New(
@@ -11,4 +13,29 @@ var (
 	) // E.g, Client was closed, a new client should be spawned
 
 	ErrPublish = errors.New("unable to publish msg")
+
+	ErrDecodeMsg      = errors.New("unable to decode msg")
+	ErrDecodePayload  = errors.New("unable to decode payload")
+	ErrInvalidPayload = errors.New("invalid payload")
 )
+
+// isPayloadError reports whether every non nil error is due to a payload that
+// could not be decoded or validated. Such messages are captured and skipped,
+// as handling them again would fail the same way.
+func isPayloadError(errs ...error) bool {
+	var found bool
+
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+
+		if !errors.Is(err, ErrDecodePayload) && !errors.Is(err, ErrInvalidPayload) {
+			return false
+		}
+
+		found = true
+	}
+
+	return found
+}
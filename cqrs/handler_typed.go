@@ -0,0 +1,102 @@
+package cqrs
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+type (
+	// PayloadValidator is implemented by payloads that can check themselves
+	// once decoded by typed handlers.
+	PayloadValidator interface {
+		Validate() error
+	}
+
+	TypedCommandHandlerFunc[P any] func(ctx context.Context, cmd Command, payload P, eventer Eventer) error
+
+	TypedEventHandlerFunc[P any] func(ctx context.Context, event Event, payload P) error
+
+	TypedSagaHandlerFunc[P any] func(ctx context.Context, event Event, payload P, commander Commander) error
+)
+
+// NewTypedCommandHandler creates a new CommandHandler whose payload is decoded
+// into P with the codec of the bus the command was received from. If P, or a
+// pointer to it, implements PayloadValidator the payload is validated too, and
+// a null payload decoding into a nil pointer is rejected as ErrInvalidPayload.
+//
+// Payloads that fail to decode or validate are not handed to fn. The error is
+// captured in the errors topic and consumption goes on, even if the container
+// was set up with ContainerMustProcessOrFail.
+func NewTypedCommandHandler[P any](
+	v, r, a string,
+	fn TypedCommandHandlerFunc[P],
+) CommandHandler {
+	return NewCommandHandler(v, r, a, func(ctx context.Context, cmd Command, eventer Eventer) error {
+		payload, err := decodePayload[P](cmd.recvMsg)
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, cmd, payload, eventer)
+	})
+}
+
+// NewTypedEventHandler is like NewTypedCommandHandler, for events.
+func NewTypedEventHandler[P any](v, r, a string, fn TypedEventHandlerFunc[P]) EventHandler {
+	return NewEventHandler(v, r, a, func(ctx context.Context, event Event) error {
+		payload, err := decodePayload[P](event.recvMsg)
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, event, payload)
+	})
+}
+
+// NewTypedSagaHandler is like NewTypedCommandHandler, for sagas.
+func NewTypedSagaHandler[P any](
+	v, r, a string,
+	fn TypedSagaHandlerFunc[P],
+	opts *SagaHandlerOpts,
+) SagaHandler {
+	return NewSagaHandler(v, r, a, func(ctx context.Context, event Event, commander Commander) error {
+		payload, err := decodePayload[P](event.recvMsg)
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, event, payload, commander)
+	}, opts)
+}
+
+func decodePayload[P any](m recvMsg) (P, error) {
+	payload, err := Decode[P](m.payloadCodec(), m.Payload())
+	if err != nil {
+		return payload, fmt.Errorf("%w: %v", ErrDecodePayload, err)
+	}
+
+	validator, ok := any(payload).(PayloadValidator)
+	if ok && isNilPointer(payload) {
+		// a null payload decodes into a nil pointer, which Validate would
+		// dereference
+		return payload, fmt.Errorf("%w: null payload", ErrInvalidPayload)
+	}
+
+	if !ok {
+		validator, ok = any(&payload).(PayloadValidator)
+	}
+
+	if ok {
+		if err := validator.Validate(); err != nil {
+			return payload, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+	}
+
+	return payload, nil
+}
+
+func isNilPointer(v any) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
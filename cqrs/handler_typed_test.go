@@ -0,0 +1,237 @@
+package cqrs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sonirico/vago/fp"
+	"github.com/sonirico/vago/rp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type placeOrderCommand struct {
+	Symbol string  `json:"symbol"`
+	Qty    float64 `json:"qty"`
+}
+
+func (c placeOrderCommand) Validate() error {
+	if c.Qty <= 0 {
+		return errors.New("qty must be positive")
+	}
+	return nil
+}
+
+type cancelOrderCommand struct {
+	OrderID string `json:"order_id"`
+}
+
+func (c *cancelOrderCommand) Validate() error {
+	if c.OrderID == "" {
+		return errors.New("order_id is required")
+	}
+	return nil
+}
+
+type capturedError struct {
+	Kind    string `json:"kind"`
+	Handler *struct {
+		Action string `json:"action"`
+	} `json:"handler"`
+	Payload struct {
+		Value json.RawMessage `json:"value"`
+	} `json:"payload"`
+}
+
+func capturedErrors(t *testing.T, producer *rp.MemoryProducer) []capturedError {
+	t.Helper()
+
+	var res []capturedError
+	for _, msg := range producer.Data() {
+		require.Equal(t, TopicErrors, msg.Topic)
+
+		var e capturedError
+		require.NoError(t, json.Unmarshal(msg.Value, &e))
+		res = append(res, e)
+	}
+
+	return res
+}
+
+func Test_TypedHandlers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := newDiscardLogger()
+	producer := rp.NewMemoryProducer()
+
+	container := NewContainer(log,
+		ContainerWithErrorProducer(producer),
+		ContainerMustProcessOrFail(),
+		ContainerSkipUndecodable(),
+		ContainerDisableAPM(),
+	)
+
+	doneC := make(chan error, 1)
+	go func() { doneC <- container.Done() }()
+
+	var (
+		orders   []placeOrderCommand
+		placed   []placeOrderCommand
+		sagaSeen []string
+	)
+
+	commands := NewMemoryCommandBus("commands", "commands.orders", log)
+	commands.CommandHandler(NewTypedCommandHandler("0", "order", "place",
+		func(ctx context.Context, _ Command, payload placeOrderCommand, eventer Eventer) error {
+			orders = append(orders, payload)
+			eventer.Event(ctx, "events", NewSimpleEvent("0", "order", "placed", payload, nil))
+			return nil
+		},
+	))
+
+	events := NewMemoryEventBus("events", "events.orders", log)
+	events.
+		EventHandler(NewTypedEventHandler("0", "order", "placed",
+			func(_ context.Context, _ Event, payload placeOrderCommand) error {
+				placed = append(placed, payload)
+				return nil
+			},
+		)).
+		SagaHandler(NewTypedSagaHandler("0", "order", "placed",
+			func(_ context.Context, _ Event, payload placeOrderCommand, _ Commander) error {
+				sagaSeen = append(sagaSeen, payload.Symbol)
+				return nil
+			},
+			nil,
+		))
+
+	require.NoError(t, container.CommandBus(commands).EventBus(events).Start(ctx))
+	require.Eventually(t, func() bool {
+		return commands.subscription() != nil && events.subscription() != nil
+	}, time.Second, time.Millisecond)
+
+	send := func(payload any) {
+		require.NoError(t, container.Command(ctx, "commands",
+			NewSimpleCommand("0", "order", "place", payload, nil)))
+	}
+
+	send(placeOrderCommand{Symbol: "BTC", Qty: 1})
+	send(map[string]any{"symbol": "ETH", "qty": "many"})
+	send(placeOrderCommand{Symbol: "ETH", Qty: 0})
+	require.NoError(t, container.Event(ctx, "events",
+		NewSimpleEvent("0", "order", "placed", []int{1}, nil)))
+	require.NoError(t, commands.publish(ctx, rp.Msg{Value: []byte("[1]")}))
+	send(placeOrderCommand{Symbol: "SOL", Qty: 2})
+
+	// Bad payloads neither reach the handlers nor stop consumption
+	assert.Equal(t, []placeOrderCommand{{"BTC", 1}, {"SOL", 2}}, orders)
+	assert.Equal(t, []placeOrderCommand{{"BTC", 1}, {"SOL", 2}}, placed)
+	assert.Equal(t, []string{"BTC", "SOL"}, sagaSeen)
+
+	captured := capturedErrors(t, producer)
+	require.Len(t, captured, 5)
+
+	assert.Equal(t, KindCommands, captured[0].Kind)
+	assert.Equal(t, "place", captured[0].Handler.Action)
+
+	assert.Equal(t, KindCommands, captured[1].Kind)
+	assert.Equal(t, "place", captured[1].Handler.Action)
+
+	// Both the event and the saga handler fail to decode the event
+	for _, e := range captured[2:4] {
+		assert.Equal(t, KindEvents, e.Kind)
+		assert.Equal(t, "placed", e.Handler.Action)
+	}
+
+	assert.Equal(t, KindCommands, captured[4].Kind)
+	assert.Nil(t, captured[4].Handler)
+	assert.JSONEq(t, `[1]`, string(captured[4].Payload.Value))
+
+	container.Close()
+	assert.NoError(t, <-doneC)
+}
+
+func Test_TypedHandler_HandlerErrors(t *testing.T) {
+	boom := errors.New("boom")
+
+	h := NewTypedCommandHandler("0", "order", "place",
+		func(context.Context, Command, placeOrderCommand, Eventer) error {
+			return boom
+		},
+	)
+
+	cmd := NewRecvCommand("id", "0", "order", "place", time.Now(),
+		json.RawMessage(`{"symbol":"BTC","qty":1}`), fp.None[string](), nil, 0, time.Now())
+
+	err := h.Handle(context.Background(), cmd, nil)
+	assert.ErrorIs(t, err, boom)
+	assert.False(t, isPayloadError(err))
+
+	cmd = NewRecvCommand("id", "0", "order", "place", time.Now(),
+		json.RawMessage(`{"symbol":"BTC","qty":-1}`), fp.None[string](), nil, 0, time.Now())
+
+	err = h.Handle(context.Background(), cmd, nil)
+	assert.ErrorIs(t, err, ErrInvalidPayload)
+	assert.True(t, isPayloadError(err))
+
+	cmd = NewRecvCommand("id", "0", "order", "place", time.Now(),
+		json.RawMessage(`{"symbol":"BTC","qty":"many"}`), fp.None[string](), nil, 0, time.Now())
+
+	err = h.Handle(context.Background(), cmd, nil)
+	assert.ErrorIs(t, err, ErrDecodePayload)
+	assert.True(t, isPayloadError(err))
+}
+
+func Test_TypedHandler_PointerPayload(t *testing.T) {
+	decode := func(t *testing.T, h CommandHandler, payload string) error {
+		t.Helper()
+
+		cmd := NewRecvCommand("id", "0", "order", "place", time.Now(),
+			json.RawMessage(payload), fp.None[string](), nil, 0, time.Now())
+
+		return h.Handle(context.Background(), cmd, nil)
+	}
+
+	t.Run("Value receiver", func(t *testing.T) {
+		var got *placeOrderCommand
+		h := NewTypedCommandHandler("0", "order", "place",
+			func(_ context.Context, _ Command, payload *placeOrderCommand, _ Eventer) error {
+				got = payload
+				return nil
+			},
+		)
+
+		require.NoError(t, decode(t, h, `{"symbol":"BTC","qty":1}`))
+		assert.Equal(t, &placeOrderCommand{Symbol: "BTC", Qty: 1}, got)
+
+		assert.ErrorIs(t, decode(t, h, `{"symbol":"BTC","qty":0}`), ErrInvalidPayload)
+		assert.ErrorIs(t, decode(t, h, `null`), ErrInvalidPayload)
+	})
+
+	t.Run("Pointer receiver", func(t *testing.T) {
+		h := NewTypedCommandHandler("0", "order", "cancel",
+			func(context.Context, Command, *cancelOrderCommand, Eventer) error {
+				return nil
+			},
+		)
+
+		require.NoError(t, decode(t, h, `{"order_id":"42"}`))
+		assert.ErrorIs(t, decode(t, h, `{}`), ErrInvalidPayload)
+		assert.ErrorIs(t, decode(t, h, `null`), ErrInvalidPayload)
+	})
+
+	t.Run("Pointer receiver, value payload", func(t *testing.T) {
+		h := NewTypedCommandHandler("0", "order", "cancel",
+			func(context.Context, Command, cancelOrderCommand, Eventer) error {
+				return nil
+			},
+		)
+
+		require.NoError(t, decode(t, h, `{"order_id":"42"}`))
+		assert.ErrorIs(t, decode(t, h, `{}`), ErrInvalidPayload)
+	})
+}
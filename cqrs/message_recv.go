@@ -36,6 +36,10 @@ type recvMsg struct {
 	recordKey       []byte
 	recordPartition int32
 	recordTs        time.Time
+
+	// codec is the one of the bus the message was received from, used to
+	// decode the payload
+	codec Codec
 }
 
 func (m recvMsg) User() fp.Option[string] {
@@ -48,6 +52,13 @@ func (m recvMsg) Action() string   { return m.A }
 func (m recvMsg) Payload() []byte  { return m.P }
 func (m recvMsg) ID() string       { return m.I }
 
+func (m recvMsg) payloadCodec() Codec {
+	if m.codec == nil {
+		return NewJson()
+	}
+	return m.codec
+}
+
 func (m recvMsg) String() string {
 	b, _ := json.Marshal(m)
 	return string(b)
@@ -22,6 +22,7 @@
 //   - db_clickhouse.go: ClickHouse support
 //   - db_mongo.go: MongoDB support
 //   - db_redis.go: Redis support
+//   - db_redis_checkpoint.go: Redis checkpoints for streams.ResumablePipe
 //
 // Utilities:
 //   - utils_bulk_insert.go, utils_bulk_update.go, utils_bulk_upsert.go: Bulk DML
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sonirico/vago/streams"
+)
+
+// RedisCheckpointer is a streams.Checkpointer that stores the offset of a
+// resumable pipe under a Redis key, so a job can resume on another host.
+type RedisCheckpointer struct {
+	client redis.Cmdable
+	key    string
+}
+
+// NewRedisCheckpointer creates a new RedisCheckpointer storing the offset at
+// key.
+func NewRedisCheckpointer(client redis.Cmdable, key string) *RedisCheckpointer {
+	return &RedisCheckpointer{client: client, key: key}
+}
+
+func (c *RedisCheckpointer) Load(ctx context.Context) (int64, error) {
+	offset, err := c.client.Get(ctx, c.key).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return offset, err
+}
+
+func (c *RedisCheckpointer) Save(ctx context.Context, offset int64) error {
+	return c.client.Set(ctx, c.key, offset, 0).Err()
+}
+
+var _ streams.Checkpointer = new(RedisCheckpointer)
@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRedis keeps the keys written through Set in memory. Commands other than
+// Get and Set are not implemented and panic through the nil Cmdable.
+type fakeRedis struct {
+	redis.Cmdable
+	values map[string]string
+	ttls   map[string]time.Duration
+	err    error
+}
+
+func newFakeRedis() *fakeRedis {
+	return &fakeRedis{
+		values: make(map[string]string),
+		ttls:   make(map[string]time.Duration),
+	}
+}
+
+func (f *fakeRedis) Get(_ context.Context, key string) *redis.StringCmd {
+	if f.err != nil {
+		return redis.NewStringResult("", f.err)
+	}
+
+	value, ok := f.values[key]
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+
+	return redis.NewStringResult(value, nil)
+}
+
+func (f *fakeRedis) Set(
+	_ context.Context,
+	key string,
+	value any,
+	expiration time.Duration,
+) *redis.StatusCmd {
+	if f.err != nil {
+		return redis.NewStatusResult("", f.err)
+	}
+
+	f.values[key] = fmt.Sprint(value)
+	f.ttls[key] = expiration
+
+	return redis.NewStatusResult("OK", nil)
+}
+
+func TestRedisCheckpointer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Missing key starts from zero", func(t *testing.T) {
+		c := NewRedisCheckpointer(newFakeRedis(), "jobs:export")
+
+		offset, err := c.Load(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, offset)
+	})
+
+	t.Run("Save and load", func(t *testing.T) {
+		client := newFakeRedis()
+		c := NewRedisCheckpointer(client, "jobs:export")
+
+		require.NoError(t, c.Save(ctx, 42))
+		require.NoError(t, c.Save(ctx, 1024))
+
+		offset, err := c.Load(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1024), offset)
+
+		assert.Equal(t, "1024", client.values["jobs:export"])
+		assert.Zero(t, client.ttls["jobs:export"], "checkpoints must not expire")
+	})
+
+	t.Run("Keys are independent", func(t *testing.T) {
+		client := newFakeRedis()
+
+		require.NoError(t, NewRedisCheckpointer(client, "a").Save(ctx, 1))
+
+		offset, err := NewRedisCheckpointer(client, "b").Load(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, offset)
+	})
+
+	t.Run("Corrupted value", func(t *testing.T) {
+		client := newFakeRedis()
+		client.values["jobs:export"] = "not a number"
+
+		_, err := NewRedisCheckpointer(client, "jobs:export").Load(ctx)
+		assert.Error(t, err)
+	})
+
+	t.Run("Client errors", func(t *testing.T) {
+		errConn := errors.New("connection refused")
+
+		client := newFakeRedis()
+		client.err = errConn
+		c := NewRedisCheckpointer(client, "jobs:export")
+
+		_, err := c.Load(ctx)
+		assert.ErrorIs(t, err, errConn)
+		assert.ErrorIs(t, c.Save(ctx, 1), errConn)
+	})
+}
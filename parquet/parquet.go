@@ -0,0 +1,12 @@
+// Package parquet reads and writes parquet files as streams.
+//
+// Reader and Writer implement the ReadStream and WriteStream interfaces of
+// the streams package, so parquet files can be consumed, piped and
+// multicast like any other stream. Columns are mapped through the `parquet`
+// struct tags of the row type.
+//
+// These are the Parquet[T] and ParquetWriter[T] streams one would look for in
+// the streams package. They live in their own module instead, as
+// parquet.NewReader and parquet.NewWriter, so that importing streams does not
+// pull in the parquet codecs.
+package parquet
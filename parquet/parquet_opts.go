@@ -0,0 +1,92 @@
+package parquet
+
+import (
+	"context"
+
+	pq "github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress"
+)
+
+// Compression is the codec used to compress the pages of a parquet file.
+type Compression string
+
+const (
+	Uncompressed Compression = "uncompressed"
+	Snappy       Compression = "snappy"
+	Gzip         Compression = "gzip"
+	Zstd         Compression = "zstd"
+	LZ4          Compression = "lz4"
+	Brotli       Compression = "brotli"
+)
+
+func (c Compression) codec() compress.Codec {
+	switch c {
+	case Uncompressed:
+		return &pq.Uncompressed
+	case Gzip:
+		return &pq.Gzip
+	case Zstd:
+		return &pq.Zstd
+	case LZ4:
+		return &pq.Lz4Raw
+	case Brotli:
+		return &pq.Brotli
+	default:
+		return &pq.Snappy
+	}
+}
+
+type ReaderOpt func(*readerOpts)
+
+type readerOpts struct {
+	ctx       context.Context
+	batchSize int
+}
+
+func (fn ReaderOpt) apply(o *readerOpts) {
+	fn(o)
+}
+
+// WithContext stops reading once ctx is done, reporting ctx.Err() through
+// Err.
+func WithContext(ctx context.Context) ReaderOpt {
+	return func(o *readerOpts) {
+		o.ctx = ctx
+	}
+}
+
+// WithReadBatch sets how many rows are decoded from the file at once.
+// Defaults to 128.
+func WithReadBatch(size int) ReaderOpt {
+	return func(o *readerOpts) {
+		o.batchSize = size
+	}
+}
+
+type WriterOpt func(*writerOpts)
+
+type writerOpts struct {
+	rowGroupSize int64
+	compression  Compression
+}
+
+func (fn WriterOpt) apply(o *writerOpts) {
+	fn(o)
+}
+
+// WithRowGroupSize caps the number of rows per row group. Smaller row groups
+// lower the memory held by the writer at the cost of a larger footer.
+// Defaults to no limit other than calls to Flush.
+func WithRowGroupSize(rows int64) WriterOpt {
+	return func(o *writerOpts) {
+		o.rowGroupSize = rows
+	}
+}
+
+// WithCompression sets the codec used for columns without a compression in
+// their `parquet` tag. Defaults to Snappy.
+func WithCompression(c Compression) WriterOpt {
+	return func(o *writerOpts) {
+		o.compression = c
+	}
+}
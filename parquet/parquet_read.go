@@ -0,0 +1,200 @@
+package parquet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+
+	pq "github.com/parquet-go/parquet-go"
+
+	"github.com/sonirico/vago/streams"
+)
+
+// Reader is a ReadStream that reads the rows of a parquet file into values of T, mapping
+// columns through the `parquet` struct tags of T, e.g. `parquet:"id"` or
+// `parquet:"note,optional"`. Rows are decoded in batches, so only a batch is
+// held in memory at a time.
+//
+// Parquet keeps its metadata in a footer, so the file must be readable at
+// random offsets. Sources implementing io.ReaderAt and io.Seeker, such as
+// *os.File, are read in place; any other source is first spooled to a
+// temporary file which is removed on Close.
+type Reader[T any] struct {
+	ctx       context.Context
+	r         io.ReadCloser
+	reader    *pq.GenericReader[T]
+	spool     *os.File
+	batch     []T
+	pos       int
+	batchSize int
+	current   T
+	err       error
+	started   bool
+	done      bool
+}
+
+// NewReader creates a new Reader reading from r. The file footer is not
+// read until the first call to Next.
+func NewReader[T any](r io.ReadCloser, opts ...ReaderOpt) *Reader[T] {
+	optsDef := readerOpts{
+		ctx:       context.Background(),
+		batchSize: 128,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	if optsDef.batchSize < 1 {
+		optsDef.batchSize = 1
+	}
+
+	return &Reader[T]{
+		ctx:       optsDef.ctx,
+		r:         r,
+		batchSize: optsDef.batchSize,
+	}
+}
+
+func (s *Reader[T]) Next() bool {
+	if s.done {
+		return false
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		return s.fail(err)
+	}
+
+	if !s.started {
+		s.started = true
+
+		if err := s.open(); err != nil {
+			return s.fail(err)
+		}
+	}
+
+	if s.pos >= len(s.batch) {
+		if s.batch == nil {
+			s.batch = make([]T, s.batchSize)
+		}
+
+		n, err := s.reader.Read(s.batch[:cap(s.batch)])
+		s.batch = s.batch[:n]
+		s.pos = 0
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return s.fail(fmt.Errorf("parquet read: %w", err))
+		}
+
+		if n == 0 {
+			s.done = true
+			return false
+		}
+	}
+
+	s.current = s.batch[s.pos]
+	s.pos++
+	return true
+}
+
+func (s *Reader[T]) fail(err error) bool {
+	s.err = err
+	s.done = true
+	return false
+}
+
+// open locates the footer of the file and prepares the row reader.
+func (s *Reader[T]) open() (err error) {
+	source, size, err := s.source()
+	if err != nil {
+		return err
+	}
+
+	file, err := pq.OpenFile(source, size)
+	if err != nil {
+		return fmt.Errorf("parquet open: %w", err)
+	}
+
+	// the reader panics when the file schema cannot be converted to T
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("parquet schema: %v", r)
+		}
+	}()
+
+	s.reader = pq.NewGenericReader[T](file)
+	return nil
+}
+
+// source returns a random access view of the input, spooling it to a
+// temporary file when it does not support one.
+func (s *Reader[T]) source() (io.ReaderAt, int64, error) {
+	if ra, ok := s.r.(interface {
+		io.ReaderAt
+		io.Seeker
+	}); ok {
+		size, err := ra.Seek(0, io.SeekEnd)
+		if err != nil {
+			return nil, 0, fmt.Errorf("parquet seek: %w", err)
+		}
+		return ra, size, nil
+	}
+
+	spool, err := os.CreateTemp("", "vago-parquet-*")
+	if err != nil {
+		return nil, 0, fmt.Errorf("parquet spool: %w", err)
+	}
+	s.spool = spool
+
+	size, err := io.Copy(spool, s.r)
+	if err != nil {
+		return nil, 0, fmt.Errorf("parquet spool: %w", err)
+	}
+
+	return spool, size, nil
+}
+
+func (s *Reader[T]) Data() T {
+	return s.current
+}
+
+func (s *Reader[T]) Err() error {
+	return s.err
+}
+
+// Close releases the row reader, removes the spooled copy of the input if
+// any, and closes the underlying reader.
+func (s *Reader[T]) Close() error {
+	var errs []error
+
+	if s.reader != nil {
+		errs = append(errs, s.reader.Close())
+		s.reader = nil
+	}
+
+	if s.spool != nil {
+		errs = append(errs, s.spool.Close(), os.Remove(s.spool.Name()))
+		s.spool = nil
+	}
+
+	if s.r != nil {
+		errs = append(errs, s.r.Close())
+		s.r = nil
+	}
+
+	s.done = true
+	return errors.Join(errs...)
+}
+
+func (s *Reader[T]) Iter() iter.Seq[T] {
+	return streams.Iter(s)
+}
+
+func (s *Reader[T]) Iter2() iter.Seq2[T, error] {
+	return streams.Iter2(s)
+}
+
+var _ streams.ReadStream[any] = new(Reader[any])
@@ -0,0 +1,302 @@
+package parquet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	pq "github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/sonirico/vago/streams"
+)
+
+type parquetTrade struct {
+	ID     int64     `parquet:"id"`
+	Symbol string    `parquet:"symbol,dict"`
+	Price  float64   `parquet:"price"`
+	Note   *string   `parquet:"note,optional"`
+	At     time.Time `parquet:"at,timestamp(millisecond)"`
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func parquetTrades(n int) []parquetTrade {
+	note := "manual"
+	base := time.Date(2025, 12, 8, 12, 0, 0, 0, time.UTC)
+
+	trades := make([]parquetTrade, n)
+	for i := range trades {
+		trades[i] = parquetTrade{
+			ID:     int64(i + 1),
+			Symbol: []string{"BTC", "ETH"}[i%2],
+			Price:  float64(i) * 1.5,
+			At:     base.Add(time.Duration(i) * time.Minute),
+		}
+		if i%3 == 0 {
+			trades[i].Note = &note
+		}
+	}
+
+	return trades
+}
+
+func TestReader_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name        string
+		rows        int
+		compression Compression
+		batch       int
+	}{
+		{name: "Empty", rows: 0},
+		{name: "Single row", rows: 1},
+		{name: "Snappy", rows: 300, compression: Snappy},
+		{name: "Zstd", rows: 300, compression: Zstd},
+		{name: "Gzip small batches", rows: 50, compression: Gzip, batch: 7},
+		{name: "Uncompressed", rows: 10, compression: Uncompressed},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			trades := parquetTrades(tc.rows)
+
+			var wopts []WriterOpt
+			if tc.compression != "" {
+				wopts = append(wopts, WithCompression(tc.compression))
+			}
+
+			var buf bytes.Buffer
+			w := NewWriter[parquetTrade](nopWriteCloser{&buf}, wopts...)
+
+			written, err := streams.Pipe(streams.MemReader(trades, nil), w)
+			require.NoError(t, err)
+			require.NoError(t, w.Close())
+			assert.Equal(t, int64(tc.rows), written)
+
+			var ropts []ReaderOpt
+			if tc.batch > 0 {
+				ropts = append(ropts, WithReadBatch(tc.batch))
+			}
+
+			s := NewReader[parquetTrade](io.NopCloser(&buf), ropts...)
+			result, err := streams.Consume(s)
+			require.NoError(t, err)
+			require.NoError(t, s.Close())
+
+			if tc.rows == 0 {
+				assert.Empty(t, result)
+				return
+			}
+			assert.Equal(t, trades, result)
+		})
+	}
+}
+
+func TestWriter_RowGroupSize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trades.parquet")
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	w := NewWriter[parquetTrade](f, WithRowGroupSize(100))
+	_, err = streams.WriteAll[parquetTrade](w, parquetTrades(250))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	assert.NoError(t, w.Close(), "close must be idempotent")
+
+	f, err = os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+
+	info, err := f.Stat()
+	require.NoError(t, err)
+
+	file, err := pq.OpenFile(f, info.Size())
+	require.NoError(t, err)
+	assert.Equal(t, int64(250), file.NumRows())
+	assert.Len(t, file.RowGroups(), 3)
+}
+
+func TestReader_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trades.parquet")
+	trades := parquetTrades(20)
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	w := NewWriter[parquetTrade](f)
+	_, err = streams.Multicast(streams.MemReader(trades, nil), streams.WriteStream[parquetTrade](w))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	f, err = os.Open(path)
+	require.NoError(t, err)
+
+	s := NewReader[parquetTrade](f)
+	result, err := streams.Consume(s)
+	require.NoError(t, err)
+	assert.Equal(t, trades, result)
+	assert.Nil(t, s.spool, "files must be read in place")
+	assert.NoError(t, s.Close())
+}
+
+func TestReader_Projection(t *testing.T) {
+	type idOnly struct {
+		ID int64 `parquet:"id"`
+	}
+
+	var buf bytes.Buffer
+	w := NewWriter[parquetTrade](nopWriteCloser{&buf})
+	_, err := streams.WriteAll[parquetTrade](w, parquetTrades(3))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	result, err := streams.Consume(NewReader[idOnly](io.NopCloser(&buf)))
+	require.NoError(t, err)
+	assert.Equal(t, []idOnly{{1}, {2}, {3}}, result)
+}
+
+func TestReader_Errors(t *testing.T) {
+	t.Run("Not a parquet file", func(t *testing.T) {
+		s := NewReader[parquetTrade](io.NopCloser(bytes.NewBufferString("id,symbol\n1,BTC\n")))
+
+		assert.False(t, s.Next())
+		assert.ErrorContains(t, s.Err(), "parquet open")
+		assert.NoError(t, s.Close())
+	})
+
+	t.Run("Source error", func(t *testing.T) {
+		s := NewReader[parquetTrade](io.NopCloser(iotest.ErrReader(io.ErrUnexpectedEOF)))
+
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), io.ErrUnexpectedEOF)
+		assert.NoError(t, s.Close())
+	})
+
+	t.Run("Context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		s := NewReader[parquetTrade](io.NopCloser(&bytes.Buffer{}), WithContext(ctx))
+
+		assert.False(t, s.Next())
+		assert.True(t, errors.Is(s.Err(), context.Canceled))
+	})
+
+	t.Run("Unsupported row type", func(t *testing.T) {
+		type bad struct {
+			Updates chan int
+		}
+
+		closed := false
+		w := NewWriter[bad](closeFunc(func() error { closed = true; return nil }))
+
+		_, err := w.Write(bad{})
+		assert.ErrorContains(t, err, "parquet schema")
+		assert.ErrorContains(t, w.Flush(), "parquet schema")
+		assert.ErrorContains(t, w.Close(), "parquet schema")
+		assert.True(t, closed, "the underlying writer must be closed")
+	})
+
+	t.Run("Writer error", func(t *testing.T) {
+		w := NewWriter[parquetTrade](nopWriteCloser{&failingWriter{}}, WithRowGroupSize(1))
+
+		_, _ = streams.WriteAll[parquetTrade](w, parquetTrades(2))
+		assert.ErrorContains(t, w.Close(), "disk full")
+		assert.Error(t, w.Err())
+	})
+}
+
+func TestReader_Compressed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trades.parquet.gz")
+	trades := parquetTrades(10)
+
+	f, err := os.Create(path)
+	require.NoError(t, err)
+
+	write := streams.GzipWriter(func(wc io.WriteCloser) streams.WriteStream[parquetTrade] {
+		return NewWriter[parquetTrade](wc)
+	})
+
+	w := write(f)
+	_, err = streams.Pipe(streams.MemReader(trades, nil), w)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	f, err = os.Open(path)
+	require.NoError(t, err)
+
+	read := streams.Decompress(func(rc io.ReadCloser) streams.ReadStream[parquetTrade] {
+		return NewReader[parquetTrade](rc)
+	})
+
+	s := read(f)
+	result, err := streams.Consume(s)
+	require.NoError(t, err)
+	assert.Equal(t, trades, result)
+	assert.NoError(t, s.Close())
+}
+
+// closeFunc is an io.WriteCloser discarding writes and calling itself on
+// Close.
+type closeFunc func() error
+
+func (closeFunc) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (fn closeFunc) Close() error {
+	return fn()
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+// ExampleNewWriter demonstrates exporting a stream to parquet and reading it back.
+func ExampleNewWriter() {
+	type Sale struct {
+		Region string  `parquet:"region,dict"`
+		Amount float64 `parquet:"amount"`
+	}
+
+	sales := []Sale{
+		{"EU", 120.5},
+		{"US", 99.9},
+		{"EU", 42},
+	}
+
+	var buf bytes.Buffer
+	writer := NewWriter[Sale](nopWriteCloser{&buf}, WithCompression(Zstd))
+
+	_, _ = streams.Pipe(streams.MemReader(sales, nil), writer)
+	_ = writer.Close()
+
+	stream := NewReader[Sale](io.NopCloser(&buf))
+	defer stream.Close()
+
+	for stream.Next() {
+		sale := stream.Data()
+		fmt.Printf("%s: %.2f\n", sale.Region, sale.Amount)
+	}
+	// Output:
+	// EU: 120.50
+	// US: 99.90
+	// EU: 42.00
+}
@@ -0,0 +1,131 @@
+package parquet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	pq "github.com/parquet-go/parquet-go"
+
+	"github.com/sonirico/vago/streams"
+)
+
+// Writer is a WriteStream that encodes values of T as rows of a
+// parquet file. The schema is derived from the `parquet` struct tags of T,
+// which also accept per-column options such as `optional`, `snappy` or
+// `dict`.
+//
+// Rows are buffered in memory until a row group is complete, so the row group
+// size bounds the memory used by the writer. Write reports one unit per row
+// since encoded sizes are only known once a row group is flushed.
+type Writer[T any] struct {
+	w      io.WriteCloser
+	writer *pq.GenericWriter[T]
+	row    []T
+	err    error
+	closed bool
+}
+
+// NewWriter creates a new Writer writing to w. The file is
+// only valid once Close has written its footer. If no schema can be derived
+// from T, every call fails with the schema error and Close only closes w.
+func NewWriter[T any](w io.WriteCloser, opts ...WriterOpt) *Writer[T] {
+	optsDef := writerOpts{
+		compression: Snappy,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	options := []pq.WriterOption{
+		pq.Compression(optsDef.compression.codec()),
+	}
+
+	if optsDef.rowGroupSize > 0 {
+		options = append(options, pq.MaxRowsPerRowGroup(optsDef.rowGroupSize))
+	}
+
+	writer, err := newGenericWriter[T](w, options)
+
+	return &Writer[T]{
+		w:      w,
+		writer: writer,
+		row:    make([]T, 1),
+		err:    err,
+	}
+}
+
+// newGenericWriter prepares the row writer, turning the panic raised when T
+// cannot be converted to a parquet schema into an error.
+func newGenericWriter[T any](w io.Writer, options []pq.WriterOption) (writer *pq.GenericWriter[T], err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("parquet schema: %v", r)
+		}
+	}()
+
+	return pq.NewGenericWriter[T](w, options...), nil
+}
+
+// Write buffers x as a new row, flushing the row group once it is full.
+func (w *Writer[T]) Write(x T) (int64, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	w.row[0] = x
+
+	n, err := w.writer.Write(w.row)
+	if err != nil {
+		w.err = fmt.Errorf("parquet write: %w", err)
+		return int64(n), w.err
+	}
+
+	return int64(n), nil
+}
+
+// Flush writes the buffered rows as a row group.
+func (w *Writer[T]) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+
+	if err := w.writer.Flush(); err != nil {
+		w.err = fmt.Errorf("parquet flush: %w", err)
+	}
+
+	return w.err
+}
+
+// Err returns the current error state
+func (w *Writer[T]) Err() error {
+	return w.err
+}
+
+// Close writes the pending rows and the file footer, then closes the
+// underlying writer. The underlying writer is closed even if the footer
+// could not be written.
+func (w *Writer[T]) Close() error {
+	if w.closed {
+		return w.err
+	}
+	w.closed = true
+
+	var errs []error
+
+	if w.err == nil {
+		if err := w.writer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("parquet close: %w", err))
+		}
+	} else {
+		errs = append(errs, w.err)
+	}
+
+	errs = append(errs, w.w.Close())
+
+	w.err = errors.Join(errs...)
+	return w.err
+}
+
+var _ streams.WriteStream[any] = new(Writer[any])
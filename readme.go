@@ -38,7 +38,7 @@ const title = `
 This project leverages Go workspaces to provide **isolated dependencies** for each module. This means:
 
 - **Lightweight imports**: When you import ` + "`fp`" + ` or ` + "`streams`" + `, you won't download database drivers or logging dependencies
-- **Modular design**: Each module (` + "`db`" + `, ` + "`lol`" + `, ` + "`num`" + `) maintains its own ` + "`go.mod`" + ` with specific dependencies
+- **Modular design**: Each module (` + "`db`" + `, ` + "`lol`" + `, ` + "`num`" + `, ` + "`compress`" + `, ` + "`parquet`" + `) maintains its own ` + "`go.mod`" + ` with specific dependencies
 - **Zero bloat**: Use only what you need without carrying unnecessary dependencies
 - **Fast builds**: Smaller dependency graphs lead to faster compilation and smaller binaries
 
@@ -49,21 +49,24 @@ This project leverages Go workspaces to provide **isolated dependencies** for ea
 `
 
 var moduleEmojis = map[string]string{
-	"clock":   "⏰",
-	"codec":   "🔄",
-	"cond":    "🔀",
-	"ent":     "🪾",
-	"fp":      "🪄",
-	"maps":    "🗝️",
-	"opts":    "⚙️",
-	"ptr":     "👉",
-	"slices":  "⛓️",
-	"streams": "🌊",
-	"str":     "📝",
-	"lol":     "📋",
-	"num":     "🔢",
-	"db":      "🗃️",
-	"zero":    "🔞",
+	"clock":    "⏰",
+	"codec":    "🔄",
+	"compress": "🗜️",
+	"cond":     "🔀",
+	"cqrs":     "📨",
+	"ent":      "🪾",
+	"fp":       "🪄",
+	"maps":     "🗝️",
+	"opts":     "⚙️",
+	"parquet":  "🧱",
+	"ptr":      "👉",
+	"slices":   "⛓️",
+	"streams":  "🌊",
+	"str":      "📝",
+	"lol":      "📋",
+	"num":      "🔢",
+	"db":       "🗃️",
+	"zero":     "🔞",
 }
 
 var moduleDescriptions = map[string]string{
@@ -345,7 +348,7 @@ func extractSourceWithComments(
 // readme generates the complete README.md file for the vago project.
 func readme() {
 	modules := []string{
-		"clock", "codec", "cond", "ent", "fp", "maps", "opts", "ptr", "slices", "streams", "str", "lol", "num", "db", "zero",
+		"clock", "codec", "compress", "cond", "cqrs", "ent", "fp", "maps", "opts", "ptr", "slices", "streams", "str", "lol", "num", "db", "parquet", "zero",
 	}
 
 	// Open README.md for writing (truncate if exists)
@@ -0,0 +1,77 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Checkpointer persists the offset of the last item committed by a
+	// ResumablePipe, so that a job can resume from it after a crash.
+	Checkpointer interface {
+		// Load returns the last saved offset, or zero if none was saved.
+		Load(ctx context.Context) (int64, error)
+		// Save persists offset as the last committed one.
+		Save(ctx context.Context, offset int64) error
+	}
+
+	// FileCheckpointer is a Checkpointer that stores the offset in a file.
+	FileCheckpointer struct {
+		path string
+	}
+)
+
+// NewFileCheckpointer creates a new FileCheckpointer storing the offset at
+// path. Saves write a temporary file next to it and rename it over path, so a
+// crash never leaves a truncated checkpoint behind.
+func NewFileCheckpointer(path string) *FileCheckpointer {
+	return &FileCheckpointer{path: path}
+}
+
+func (c *FileCheckpointer) Load(context.Context) (int64, error) {
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return 0, nil
+		}
+		return 0, err
+	}
+
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid checkpoint %s: %w", c.path, err)
+	}
+
+	return offset, nil
+}
+
+func (c *FileCheckpointer) Save(_ context.Context, offset int64) error {
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.WriteString(strconv.FormatInt(offset, 10)); err == nil {
+		err = tmp.Sync()
+	}
+
+	if errClose := tmp.Close(); err == nil {
+		err = errClose
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
+
+var _ Checkpointer = new(FileCheckpointer)
@@ -0,0 +1,47 @@
+package streams
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileCheckpointer(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Missing checkpoint starts from zero", func(t *testing.T) {
+		cp := NewFileCheckpointer(filepath.Join(t.TempDir(), "job.offset"))
+
+		offset, err := cp.Load(ctx)
+		require.NoError(t, err)
+		assert.Zero(t, offset)
+	})
+
+	t.Run("Saves and loads the offset", func(t *testing.T) {
+		dir := t.TempDir()
+		cp := NewFileCheckpointer(filepath.Join(dir, "job.offset"))
+
+		require.NoError(t, cp.Save(ctx, 42))
+		require.NoError(t, cp.Save(ctx, 1024))
+
+		offset, err := cp.Load(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1024), offset)
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "temporary files are cleaned up")
+	})
+
+	t.Run("Invalid checkpoint", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "job.offset")
+		require.NoError(t, os.WriteFile(path, []byte("nope"), 0o644))
+
+		_, err := NewFileCheckpointer(path).Load(ctx)
+		assert.ErrorContains(t, err, "invalid checkpoint")
+	})
+}
@@ -0,0 +1,110 @@
+package streams
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// CSVEncoder derives CSV headers and records from the `csv` struct tags of T,
+// so types do not need a hand-written MarshalCSV. T must be a struct or a
+// pointer to a struct; nil pointers are written as empty records.
+//
+// Supported cells are strings, booleans, numbers, time.Time (formatted with
+// the `layout` tag, time.RFC3339 by default), fp.Option (None is an empty
+// cell), num.Dec and any other encoding.TextMarshaler or fmt.Stringer. Values
+// exposing an IsNil method, such as num.Dec, are written as empty cells when
+// unset. Nested structs are flattened with their field name as prefix, e.g.
+// `buyer.id`.
+//
+// The field plan is built once per type and shared by every encoder.
+type CSVEncoder[T any] struct {
+	plan *csvStruct
+	ptr  bool
+}
+
+// NewCSVEncoder creates a CSVEncoder for T.
+func NewCSVEncoder[T any]() (*CSVEncoder[T], error) {
+	t := reflect.TypeFor[T]()
+	ptr := t.Kind() == reflect.Pointer
+
+	if ptr {
+		t = t.Elem()
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("csv encoder: type %s is not a struct", reflect.TypeFor[T]())
+	}
+
+	return &CSVEncoder[T]{
+		plan: csvStructOf(t),
+		ptr:  ptr,
+	}, nil
+}
+
+// Header returns the column names of T. The returned slice must not be modified.
+func (e *CSVEncoder[T]) Header() []string {
+	return e.plan.header
+}
+
+// Record returns the cells of x, in the same order as Header.
+func (e *CSVEncoder[T]) Record(x T) ([]string, error) {
+	record := make([]string, len(e.plan.fields))
+
+	v := reflect.ValueOf(&x).Elem()
+	if e.ptr {
+		if v.IsNil() {
+			return record, nil
+		}
+		v = v.Elem()
+	}
+
+	for i, field := range e.plan.fields {
+		fv, ok := csvFieldByIndex(v, field.index, false)
+		if !ok {
+			continue
+		}
+
+		cell, err := field.encode(fv)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.name, err)
+		}
+
+		record[i] = cell
+	}
+
+	return record, nil
+}
+
+// MarshalCSV returns the header and the record of x, with the same signature
+// as the MarshalCSV method expected by TransformCSV.
+func (e *CSVEncoder[T]) MarshalCSV(x T) ([]string, []string, error) {
+	record, err := e.Record(x)
+	return e.plan.header, record, err
+}
+
+// csvMarshalFunc returns how TransformCSVStruct marshals values of T: through
+// their MarshalCSV method when T or *T implements it, or through a CSVEncoder
+// otherwise. Method sets are checked rather than a zero value, which would be
+// a nil interface when T is an interface type.
+func csvMarshalFunc[T any]() (func(T) ([]string, []string, error), error) {
+	t := reflect.TypeFor[T]()
+	marshaler := reflect.TypeFor[csvMarshaler]()
+
+	switch {
+	case t.Implements(marshaler):
+		return func(x T) ([]string, []string, error) {
+			return any(x).(csvMarshaler).MarshalCSV()
+		}, nil
+	case reflect.PointerTo(t).Implements(marshaler):
+		return func(x T) ([]string, []string, error) {
+			return any(&x).(csvMarshaler).MarshalCSV()
+		}, nil
+	}
+
+	encoder, err := NewCSVEncoder[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	return encoder.MarshalCSV, nil
+}
@@ -0,0 +1,278 @@
+package streams
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sonirico/vago/fp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// csvAmount mimics num.Dec: a struct with an unset state and a String method.
+type csvAmount struct {
+	cents int64
+	isset bool
+}
+
+func (a csvAmount) String() string {
+	return fmt.Sprintf("%d.%02d", a.cents/100, a.cents%100)
+}
+
+func (a csvAmount) IsNil() bool {
+	return !a.isset
+}
+
+type csvUser struct {
+	ID   int    `csv:"id"`
+	Name string `csv:"name"`
+}
+
+type csvAudit struct {
+	Source string `csv:"source"`
+}
+
+type csvTrade struct {
+	csvAudit
+	ID       int               `csv:"id"`
+	Amount   csvAmount         `csv:"amount"`
+	Fee      csvAmount         `csv:"fee"`
+	Date     time.Time         `csv:"date" layout:"2006-01-02"`
+	Settled  time.Time         `csv:"settled"`
+	Note     fp.Option[string] `csv:"note"`
+	Rate     fp.Option[float64]
+	Buyer    csvUser  `csv:"buyer"`
+	Seller   *csvUser `csv:"seller"`
+	internal string
+	Skipped  string `csv:"-"`
+}
+
+func TestCSVEncoder(t *testing.T) {
+	enc, err := NewCSVEncoder[csvTrade]()
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"source", "id", "amount", "fee", "date", "settled", "note", "Rate",
+		"buyer.id", "buyer.name", "seller.id", "seller.name",
+	}, enc.Header())
+
+	ts := time.Date(2025, 12, 8, 12, 30, 0, 0, time.UTC)
+
+	record, err := enc.Record(csvTrade{
+		csvAudit: csvAudit{Source: "api"},
+		ID:       7,
+		Amount:   csvAmount{cents: 1050, isset: true},
+		Date:     ts,
+		Settled:  ts,
+		Note:     fp.Some("urgent"),
+		Rate:     fp.None[float64](),
+		Buyer:    csvUser{ID: 1, Name: "Alice"},
+		internal: "hidden",
+		Skipped:  "skipped",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"api", "7", "10.50", "", "2025-12-08", "2025-12-08T12:30:00Z", "urgent", "",
+		"1", "Alice", "", "",
+	}, record)
+}
+
+func TestCSVEncoder_Pointer(t *testing.T) {
+	enc, err := NewCSVEncoder[*csvUser]()
+	require.NoError(t, err)
+
+	record, err := enc.Record(&csvUser{ID: 1, Name: "Alice"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "Alice"}, record)
+
+	record, err = enc.Record(nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", ""}, record)
+}
+
+func TestCSVEncoder_Errors(t *testing.T) {
+	_, err := NewCSVEncoder[int]()
+	assert.Error(t, err)
+
+	type unsupported struct {
+		Tags []string `csv:"tags"`
+	}
+
+	enc, err := NewCSVEncoder[unsupported]()
+	require.NoError(t, err)
+
+	_, err = enc.Record(unsupported{Tags: []string{"a"}})
+	assert.ErrorContains(t, err, "field tags: unsupported csv field type []string")
+}
+
+func TestPipeCSV_StructTags(t *testing.T) {
+	stream := MemReader([]csvUser{
+		{ID: 1, Name: "Doe, John"},
+		{ID: 2, Name: "Alice"},
+	}, nil)
+
+	var buf bytes.Buffer
+	written, err := PipeCSVStruct(stream, &buf, CSVSeparatorComma)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3), written)
+	assert.Equal(t, "id,name\n1,\"Doe, John\"\n2,Alice\n", buf.String())
+}
+
+func TestPipeCSV_InterfaceType(t *testing.T) {
+	stream := MemReader([]csvMarshaler{
+		mockCsvMarshaler{ID: 1, Name: "Alice", Email: "alice@example.com"},
+		mockCsvMarshaler{ID: 2, Name: "Bob", Email: "bob@example.com"},
+	}, nil)
+
+	var buf bytes.Buffer
+	written, err := PipeCSVStruct(stream, &buf, CSVSeparatorComma)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(3), written)
+	assert.Equal(t, "ID,Name,Email\n1,Alice,alice@example.com\n2,Bob,bob@example.com\n", buf.String())
+}
+
+// csvPointerUser marshals itself through a pointer receiver, which its tags
+// must not take precedence over.
+type csvPointerUser struct {
+	ID   int    `csv:"id"`
+	Name string `csv:"name"`
+}
+
+func (u *csvPointerUser) MarshalCSV() ([]string, []string, error) {
+	return []string{"user"}, []string{fmt.Sprintf("%d:%s", u.ID, u.Name)}, nil
+}
+
+func TestPipeCSVStruct_PointerReceiver(t *testing.T) {
+	stream := MemReader([]csvPointerUser{{ID: 1, Name: "Alice"}, {ID: 2, Name: "Bob"}}, nil)
+
+	var buf bytes.Buffer
+	_, err := PipeCSVStruct(stream, &buf, CSVSeparatorComma)
+	require.NoError(t, err)
+
+	assert.Equal(t, "user\n1:Alice\n2:Bob\n", buf.String())
+}
+
+func TestPipeCSVStruct_NotStruct(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := PipeCSVStruct(MemReader([]int{1}, nil), &buf, CSVSeparatorComma)
+	assert.ErrorContains(t, err, "type int is not a struct")
+	assert.Empty(t, buf.String())
+}
+
+func TestCSVEncoder_RoundTrip(t *testing.T) {
+	type row struct {
+		ID    int               `csv:"id"`
+		Date  time.Time         `csv:"date" layout:"2006-01-02"`
+		Note  fp.Option[string] `csv:"note"`
+		Buyer *csvUser          `csv:"buyer"`
+	}
+
+	rows := []row{
+		{
+			ID:    1,
+			Date:  time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC),
+			Note:  fp.Some("first, with comma"),
+			Buyer: &csvUser{ID: 10, Name: "Alice"},
+		},
+		{
+			ID:   2,
+			Date: time.Date(2025, 12, 9, 0, 0, 0, 0, time.UTC),
+			Note: fp.None[string](),
+		},
+	}
+
+	var buf bytes.Buffer
+	_, err := PipeCSVStruct(MemReader(rows, nil), &buf, CSVSeparatorComma)
+	require.NoError(t, err)
+
+	stream, err := CSV[row](WithCSVReader(io.NopCloser(&buf)), WithCSVHeader())
+	require.NoError(t, err)
+
+	result, err := Consume(stream)
+	require.NoError(t, err)
+	assert.Equal(t, rows, result)
+}
+
+// ExampleNewCSVEncoder demonstrates exporting structs to CSV without implementing MarshalCSV.
+func ExampleNewCSVEncoder() {
+	type address struct {
+		City string `csv:"city"`
+	}
+
+	type customer struct {
+		ID      int       `csv:"id"`
+		Name    string    `csv:"name"`
+		Since   time.Time `csv:"since" layout:"2006-01-02"`
+		Address address   `csv:"address"`
+	}
+
+	customers := []customer{
+		{1, "Alice", time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), address{"Madrid"}},
+		{2, "Bob", time.Date(2023, 7, 15, 0, 0, 0, 0, time.UTC), address{"Lisbon"}},
+	}
+
+	// PipeCSVStruct uses a CSVEncoder for types not implementing MarshalCSV
+	_, _ = PipeCSVStruct(MemReader(customers, nil), os.Stdout, CSVSeparatorComma)
+
+	// Encoders can also be used directly
+	enc, _ := NewCSVEncoder[customer]()
+	fmt.Println(strings.Join(enc.Header(), "|"))
+	// Output:
+	// id,name,since,address.city
+	// 1,Alice,2021-03-01,Madrid
+	// 2,Bob,2023-07-15,Lisbon
+	// id|name|since|address.city
+}
+
+// csvBenchTrade is marshaled both by hand and through a CSVEncoder, so both
+// paths can be compared.
+type csvBenchTrade struct {
+	ID     int       `csv:"id"`
+	Symbol string    `csv:"symbol"`
+	Price  float64   `csv:"price"`
+	Date   time.Time `csv:"date" layout:"2006-01-02"`
+}
+
+func (x csvBenchTrade) MarshalCSV() ([]string, []string, error) {
+	return []string{"id", "symbol", "price", "date"}, []string{
+		strconv.Itoa(x.ID),
+		x.Symbol,
+		strconv.FormatFloat(x.Price, 'f', -1, 64),
+		x.Date.Format("2006-01-02"),
+	}, nil
+}
+
+func BenchmarkCSVMarshal(b *testing.B) {
+	trade := csvBenchTrade{
+		ID:     1,
+		Symbol: "BTC",
+		Price:  42000.5,
+		Date:   time.Date(2025, 12, 8, 0, 0, 0, 0, time.UTC),
+	}
+
+	b.Run("MarshalCSV", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			_, _, _ = trade.MarshalCSV()
+		}
+	})
+
+	b.Run("CSVEncoder", func(b *testing.B) {
+		enc, err := NewCSVEncoder[csvBenchTrade]()
+		require.NoError(b, err)
+
+		b.ReportAllocs()
+		for b.Loop() {
+			_, _, _ = enc.MarshalCSV(trade)
+		}
+	})
+}
@@ -0,0 +1,352 @@
+package streams
+
+import (
+	"database/sql"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// csvField describes how a CSV column maps to a, possibly nested, struct
+	// field.
+	csvField struct {
+		name   string
+		index  []int
+		layout string
+		encode func(reflect.Value) (string, error)
+	}
+
+	// csvStruct is the cached field plan of a struct type.
+	csvStruct struct {
+		fields []csvField
+		byName map[string]int
+		header []string
+	}
+
+	// csvNilable is implemented by types with an explicit unset state, such
+	// as num.Dec. Unset values are written as empty cells.
+	csvNilable interface {
+		IsNil() bool
+	}
+
+	// csvOptional is implemented by fp.Option. None is written as an empty
+	// cell.
+	csvOptional interface {
+		IsNone() bool
+	}
+)
+
+var (
+	csvStructCache sync.Map // map[reflect.Type]*csvStruct
+
+	timeType            = reflect.TypeFor[time.Time]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+	stringerType        = reflect.TypeFor[fmt.Stringer]()
+	scannerType         = reflect.TypeFor[sql.Scanner]()
+	optionalType        = reflect.TypeFor[csvOptional]()
+)
+
+// csvStructOf returns the field plan of struct type t, building and caching
+// it on first use.
+//
+// Fields are mapped by their `csv:"name"` tag, by field name when untagged,
+// and skipped when tagged with `csv:"-"`. Nested structs are flattened and
+// their columns prefixed with the parent name and a dot, except for
+// untagged embedded structs. time.Time fields honour a `layout` tag, which
+// defaults to time.RFC3339.
+func csvStructOf(t reflect.Type) *csvStruct {
+	if cached, ok := csvStructCache.Load(t); ok {
+		return cached.(*csvStruct)
+	}
+
+	plan := &csvStruct{byName: make(map[string]int)}
+	plan.collect(t, "", nil)
+
+	for i, f := range plan.fields {
+		plan.byName[f.name] = i
+		plan.header = append(plan.header, f.name)
+	}
+
+	cached, _ := csvStructCache.LoadOrStore(t, plan)
+	return cached.(*csvStruct)
+}
+
+func (p *csvStruct) collect(t reflect.Type, prefix string, index []int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() && !(f.Anonymous && f.Type.Kind() == reflect.Struct && csvIsNested(f.Type)) {
+			// Exported fields of embedded unexported structs are still promoted
+			continue
+		}
+
+		tag, _, _ := strings.Cut(f.Tag.Get("csv"), ",")
+		if tag == "-" {
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = f.Name
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if csvIsNested(f.Type) {
+			nestedPrefix := prefix + name + "."
+			if f.Anonymous && tag == "" {
+				nestedPrefix = prefix
+			}
+
+			p.collect(csvDeref(f.Type), nestedPrefix, fieldIndex)
+			continue
+		}
+
+		layout := f.Tag.Get("layout")
+
+		p.fields = append(p.fields, csvField{
+			name:   prefix + name,
+			index:  fieldIndex,
+			layout: layout,
+			encode: csvEncoderFor(f.Type, layout),
+		})
+	}
+}
+
+// csvIsStruct reports whether T can be decoded by field tags.
+func csvIsStruct[T any]() bool {
+	return reflect.TypeFor[T]().Kind() == reflect.Struct
+}
+
+func csvDeref(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer {
+		return t.Elem()
+	}
+	return t
+}
+
+// csvIsNested reports whether a field of type t is flattened into several
+// columns rather than written as a single cell.
+func csvIsNested(t reflect.Type) bool {
+	t = csvDeref(t)
+
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+
+	ptr := reflect.PointerTo(t)
+
+	for _, iface := range []reflect.Type{
+		textMarshalerType, stringerType, scannerType, optionalType,
+	} {
+		if t.Implements(iface) || ptr.Implements(iface) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// csvEncoderFor builds the function writing values of type t as a cell.
+// Unsupported types yield an encoder that always fails, so that the error
+// surfaces when writing rather than when decoding the same struct.
+func csvEncoderFor(t reflect.Type, layout string) func(reflect.Value) (string, error) {
+	switch {
+	case t.Kind() == reflect.Pointer:
+		elem := csvEncoderFor(t.Elem(), layout)
+		return func(v reflect.Value) (string, error) {
+			if v.IsNil() {
+				return "", nil
+			}
+			return elem(v.Elem())
+		}
+	case t.Implements(optionalType):
+		return csvOptionEncoder(t, layout)
+	case t == timeType:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return func(v reflect.Value) (string, error) {
+			return v.Interface().(time.Time).Format(layout), nil
+		}
+	case t.Implements(textMarshalerType):
+		return func(v reflect.Value) (string, error) {
+			if n, ok := v.Interface().(csvNilable); ok && n.IsNil() {
+				return "", nil
+			}
+			text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+			return string(text), err
+		}
+	case t.Implements(stringerType):
+		return func(v reflect.Value) (string, error) {
+			if n, ok := v.Interface().(csvNilable); ok && n.IsNil() {
+				return "", nil
+			}
+			return v.Interface().(fmt.Stringer).String(), nil
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return func(v reflect.Value) (string, error) {
+			return v.String(), nil
+		}
+	case reflect.Bool:
+		return func(v reflect.Value) (string, error) {
+			return strconv.FormatBool(v.Bool()), nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(v reflect.Value) (string, error) {
+			return strconv.FormatInt(v.Int(), 10), nil
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return func(v reflect.Value) (string, error) {
+			return strconv.FormatUint(v.Uint(), 10), nil
+		}
+	case reflect.Float32, reflect.Float64:
+		bits := t.Bits()
+		return func(v reflect.Value) (string, error) {
+			return strconv.FormatFloat(v.Float(), 'f', -1, bits), nil
+		}
+	}
+
+	return func(reflect.Value) (string, error) {
+		return "", fmt.Errorf("unsupported csv field type %s", t)
+	}
+}
+
+// csvOptionEncoder writes fp.Option values, leaving None as an empty cell.
+// The contained value is reached through the Unwrap method, as the fields
+// of the option are not exported.
+func csvOptionEncoder(t reflect.Type, layout string) func(reflect.Value) (string, error) {
+	unwrap, ok := t.MethodByName("Unwrap")
+	if !ok || unwrap.Type.NumOut() != 2 {
+		return func(reflect.Value) (string, error) {
+			return "", fmt.Errorf("unsupported csv optional type %s", t)
+		}
+	}
+
+	elem := csvEncoderFor(unwrap.Type.Out(0), layout)
+
+	return func(v reflect.Value) (string, error) {
+		if v.Interface().(csvOptional).IsNone() {
+			return "", nil
+		}
+		return elem(v.Method(unwrap.Index).Call(nil)[0])
+	}
+}
+
+// csvFieldByIndex returns the field at index, allocating nil nested struct
+// pointers on the way when alloc is true. ok is false when a nil pointer was
+// found and alloc is false.
+func csvFieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+// setCSVField decodes a CSV cell into v. Empty cells leave v untouched.
+func setCSVField(v reflect.Value, s string, layout string) error {
+	if s == "" {
+		return nil
+	}
+
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return setCSVField(v.Elem(), s, layout)
+	}
+
+	if v.Type() == timeType && layout != "" {
+		ts, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(ts))
+		return nil
+	}
+
+	if v.Type().Implements(optionalType) {
+		return setCSVOption(v, s, layout)
+	}
+
+	if v.Addr().Type().Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	if v.Addr().Type().Implements(scannerType) {
+		return v.Addr().Interface().(sql.Scanner).Scan(s)
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported csv field type %s", v.Type())
+	}
+
+	return nil
+}
+
+// setCSVOption decodes a cell into an fp.Option as Some. The contained value
+// is decoded first and then handed to the JSON unmarshaler of the option, the
+// only exported way to build a Some value without knowing its type.
+func setCSVOption(v reflect.Value, s string, layout string) error {
+	unwrap, ok := v.Type().MethodByName("Unwrap")
+	if !ok || unwrap.Type.NumOut() != 2 {
+		return fmt.Errorf("unsupported csv optional type %s", v.Type())
+	}
+
+	elem := reflect.New(unwrap.Type.Out(0)).Elem()
+	if err := setCSVField(elem, s, layout); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(elem.Interface())
+	if err != nil {
+		return err
+	}
+
+	return v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data)
+}
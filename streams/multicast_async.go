@@ -0,0 +1,230 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var ErrMulticastAllFailed = errors.New("all destinations failed")
+
+// MulticastResult reports the outcome of a single MulticastAsync destination.
+type MulticastResult struct {
+	// Bytes is the number of bytes written to the destination.
+	Bytes int64
+	// Err is the write or flush error that stopped the destination, if any.
+	Err error
+}
+
+type multicaster[T any] struct {
+	opts    multicastOpts
+	ctx     context.Context
+	cancel  context.CancelFunc
+	results []MulticastResult
+	active  atomic.Int64
+
+	// readErr is set before the queues are closed, so destinations see it
+	// once they run out of items.
+	readErr error
+
+	// mu guards err, the first error stopping the whole multicast.
+	mu  sync.Mutex
+	err error
+}
+
+// MulticastAsync copies all items from a ReadStream to multiple WriteStreams
+// like Multicast, but every destination is written by its own goroutine
+// through a bounded buffer, so a slow destination only holds back the source
+// once its buffer is full.
+//
+// Failures are handled according to WithMulticastPolicy. Under
+// MulticastDropFailed the returned error is only set for read errors, or once
+// every destination has failed; check each MulticastResult for the others.
+// Destinations are flushed once the source is exhausted, but never closed. As
+// with Multicast, a read error leaves them unflushed.
+func MulticastAsync[T any](
+	src ReadStream[T],
+	destinations []WriteStream[T],
+	opts ...MulticastOpt,
+) ([]MulticastResult, error) {
+	optsDef := multicastOpts{
+		ctx:      context.Background(),
+		buffer:   64,
+		policy:   MulticastFailFast,
+		attempts: 3,
+		backoff:  100 * time.Millisecond,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	if optsDef.buffer < 0 {
+		optsDef.buffer = 0
+	}
+
+	m := &multicaster[T]{
+		opts:    optsDef,
+		results: make([]MulticastResult, len(destinations)),
+	}
+
+	if len(destinations) == 0 {
+		return m.results, nil
+	}
+
+	m.ctx, m.cancel = context.WithCancel(optsDef.ctx)
+	defer m.cancel()
+
+	m.active.Store(int64(len(destinations)))
+
+	queues := make([]chan T, len(destinations))
+
+	var wg sync.WaitGroup
+	wg.Add(len(destinations))
+
+	for i, dst := range destinations {
+		queues[i] = make(chan T, optsDef.buffer)
+
+		go func() {
+			defer wg.Done()
+			m.drain(i, dst, queues[i])
+		}()
+	}
+
+	m.readErr = m.broadcast(src, queues)
+
+	for _, queue := range queues {
+		close(queue)
+	}
+
+	wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch {
+	case m.err != nil:
+		return m.results, m.err
+	case m.readErr != nil:
+		return m.results, m.readErr
+	default:
+		return m.results, optsDef.ctx.Err()
+	}
+}
+
+// broadcast reads the source and queues every item for all destinations.
+// Sources able to abort a blocking read are bound to the multicast context,
+// so a failure or a cancellation interrupts them too.
+func (m *multicaster[T]) broadcast(src ReadStream[T], queues []chan T) error {
+	if aware, ok := src.(contextAware); ok {
+		aware.setContext(m.ctx)
+	}
+
+	for src.Next() {
+		if err := src.Err(); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read error: %w", err)
+		}
+
+		data := src.Data()
+
+		for _, queue := range queues {
+			select {
+			case queue <- data:
+			case <-m.ctx.Done():
+				return nil
+			}
+		}
+	}
+
+	if m.ctx.Err() != nil {
+		return nil
+	}
+
+	if err := src.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("read error: %w", err)
+	}
+
+	return nil
+}
+
+// drain writes the queued items to a destination and flushes it, unless the
+// source failed. Once the destination has failed, the rest of its queue is
+// discarded so the source is never blocked by it.
+func (m *multicaster[T]) drain(i int, dst WriteStream[T], queue <-chan T) {
+	result := &m.results[i]
+
+	for data := range queue {
+		if result.Err != nil || m.ctx.Err() != nil {
+			continue
+		}
+
+		err := m.attempt(func() error {
+			n, err := dst.Write(data)
+			result.Bytes += n
+			return err
+		})
+
+		if err != nil {
+			m.fail(result, fmt.Errorf("write error to destination %d: %w", i, err))
+		}
+	}
+
+	if result.Err != nil || m.ctx.Err() != nil || m.readErr != nil {
+		return
+	}
+
+	if err := m.attempt(dst.Flush); err != nil {
+		m.fail(result, fmt.Errorf("flush error for destination %d: %w", i, err))
+	}
+}
+
+// attempt calls fn, retrying it with exponential backoff under the
+// MulticastRetry policy.
+func (m *multicaster[T]) attempt(fn func() error) error {
+	backoff := m.opts.backoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || m.opts.policy != MulticastRetry || attempt >= m.opts.attempts {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-timer.C:
+		case <-m.ctx.Done():
+			timer.Stop()
+			return err
+		}
+
+		backoff *= 2
+	}
+}
+
+// fail records the error of a destination and applies the failure policy.
+func (m *multicaster[T]) fail(result *MulticastResult, err error) {
+	result.Err = err
+
+	if m.opts.policy == MulticastDropFailed {
+		if m.active.Add(-1) > 0 {
+			return
+		}
+		err = fmt.Errorf("%w: %w", ErrMulticastAllFailed, err)
+	}
+
+	m.mu.Lock()
+	if m.err == nil {
+		m.err = err
+	}
+	m.mu.Unlock()
+
+	m.cancel()
+}
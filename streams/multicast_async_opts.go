@@ -0,0 +1,68 @@
+package streams
+
+import (
+	"context"
+	"time"
+)
+
+// MulticastPolicy decides what MulticastAsync does when a destination fails.
+type MulticastPolicy int
+
+const (
+	// MulticastFailFast stops every destination on the first failure.
+	MulticastFailFast MulticastPolicy = iota
+	// MulticastDropFailed stops writing to a failed destination while the
+	// others carry on.
+	MulticastDropFailed
+	// MulticastRetry retries failed writes and flushes with exponential
+	// backoff, failing fast once the attempts are exhausted.
+	MulticastRetry
+)
+
+type MulticastOpt func(*multicastOpts)
+
+type multicastOpts struct {
+	ctx      context.Context
+	buffer   int
+	policy   MulticastPolicy
+	attempts int
+	backoff  time.Duration
+}
+
+func (fn MulticastOpt) apply(o *multicastOpts) {
+	fn(o)
+}
+
+// WithMulticastContext stops the multicast once ctx is done.
+func WithMulticastContext(ctx context.Context) MulticastOpt {
+	return func(o *multicastOpts) {
+		o.ctx = ctx
+	}
+}
+
+// WithMulticastBuffer sets how many items may be queued for each destination
+// before a slow destination holds back the source. Defaults to 64.
+func WithMulticastBuffer(size int) MulticastOpt {
+	return func(o *multicastOpts) {
+		o.buffer = size
+	}
+}
+
+// WithMulticastPolicy sets the failure policy. Defaults to MulticastFailFast.
+func WithMulticastPolicy(policy MulticastPolicy) MulticastOpt {
+	return func(o *multicastOpts) {
+		o.policy = policy
+	}
+}
+
+// WithMulticastRetry selects the MulticastRetry policy, making up to attempts
+// calls per write and waiting backoff, doubled after every failure, between
+// them. Only destinations whose writes can safely be repeated should be
+// retried.
+func WithMulticastRetry(attempts int, backoff time.Duration) MulticastOpt {
+	return func(o *multicastOpts) {
+		o.policy = MulticastRetry
+		o.attempts = attempts
+		o.backoff = backoff
+	}
+}
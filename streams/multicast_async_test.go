@@ -0,0 +1,263 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyWriter is a WriteStream that fails the writes selected by failOn and
+// optionally waits on gate before every write.
+type flakyWriter[T any] struct {
+	mu     sync.Mutex
+	items  []T
+	calls  int
+	failOn func(call int) bool
+	gate   chan struct{}
+}
+
+func (w *flakyWriter[T]) Write(item T) (int64, error) {
+	if w.gate != nil {
+		<-w.gate
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.calls++
+	if w.failOn != nil && w.failOn(w.calls) {
+		return 0, errors.New("sink unavailable")
+	}
+
+	w.items = append(w.items, item)
+	return 1, nil
+}
+
+func (w *flakyWriter[T]) Flush() error {
+	return nil
+}
+
+func (w *flakyWriter[T]) Err() error {
+	return nil
+}
+
+func (w *flakyWriter[T]) Close() error {
+	return nil
+}
+
+func (w *flakyWriter[T]) Items() []T {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]T(nil), w.items...)
+}
+
+func failAlways(int) bool {
+	return true
+}
+
+func TestMulticastAsync(t *testing.T) {
+	errRead := errors.New("read failed")
+	items := []int{1, 2, 3, 4, 5}
+
+	tests := []struct {
+		name          string
+		src           ReadStream[int]
+		failOn        []func(int) bool
+		opts          []MulticastOpt
+		expectedItems [][]int
+		expectedErrs  []bool
+		expectedErr   error
+		errContains   string
+	}{
+		{
+			name:          "All destinations succeed",
+			src:           MemReader(items, nil),
+			failOn:        []func(int) bool{nil, nil},
+			expectedItems: [][]int{items, items},
+			expectedErrs:  []bool{false, false},
+		},
+		{
+			name:          "No buffer",
+			src:           MemReader(items, nil),
+			failOn:        []func(int) bool{nil, nil},
+			opts:          []MulticastOpt{WithMulticastBuffer(0)},
+			expectedItems: [][]int{items, items},
+			expectedErrs:  []bool{false, false},
+		},
+		{
+			name:         "Fail fast",
+			src:          MemReader(items, nil),
+			failOn:       []func(int) bool{nil, failAlways},
+			expectedErrs: []bool{false, true},
+			errContains:  "write error to destination 1: sink unavailable",
+		},
+		{
+			name:          "Drop failed destination",
+			src:           MemReader(items, nil),
+			failOn:        []func(int) bool{func(call int) bool { return call == 3 }, nil},
+			opts:          []MulticastOpt{WithMulticastPolicy(MulticastDropFailed)},
+			expectedItems: [][]int{{1, 2}, items},
+			expectedErrs:  []bool{true, false},
+		},
+		{
+			name:         "Drop every destination",
+			src:          MemReader(items, nil),
+			failOn:       []func(int) bool{failAlways, failAlways},
+			opts:         []MulticastOpt{WithMulticastPolicy(MulticastDropFailed)},
+			expectedErrs: []bool{true, true},
+			expectedErr:  ErrMulticastAllFailed,
+		},
+		{
+			name:   "Retry recovers",
+			src:    MemReader(items, nil),
+			failOn: []func(int) bool{func(call int) bool { return call == 2 || call == 3 }},
+			opts: []MulticastOpt{
+				WithMulticastRetry(3, time.Millisecond),
+			},
+			expectedItems: [][]int{items},
+			expectedErrs:  []bool{false},
+		},
+		{
+			name:   "Retry exhausted",
+			src:    MemReader(items, nil),
+			failOn: []func(int) bool{nil, func(call int) bool { return call >= 2 }},
+			opts: []MulticastOpt{
+				WithMulticastRetry(2, time.Millisecond),
+			},
+			expectedErrs: []bool{false, true},
+			errContains:  "write error to destination 1",
+		},
+		{
+			name:          "Read error",
+			src:           MemReader(items, errRead),
+			failOn:        []func(int) bool{nil},
+			expectedItems: [][]int{nil},
+			expectedErrs:  []bool{false},
+			expectedErr:   errRead,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			writers := make([]*flakyWriter[int], len(tc.failOn))
+			destinations := make([]WriteStream[int], len(tc.failOn))
+
+			for i, failOn := range tc.failOn {
+				writers[i] = &flakyWriter[int]{failOn: failOn}
+				destinations[i] = writers[i]
+			}
+
+			results, err := MulticastAsync(tc.src, destinations, tc.opts...)
+
+			switch {
+			case tc.expectedErr != nil:
+				assert.ErrorIs(t, err, tc.expectedErr)
+			case tc.errContains != "":
+				assert.ErrorContains(t, err, tc.errContains)
+			default:
+				assert.NoError(t, err)
+			}
+
+			require.Len(t, results, len(destinations))
+
+			for i, result := range results {
+				assert.Equal(t, tc.expectedErrs[i], result.Err != nil, "destination %d", i)
+				assert.Equal(t, int64(len(writers[i].Items())), result.Bytes, "destination %d", i)
+
+				if tc.expectedItems != nil {
+					assert.Equal(t, tc.expectedItems[i], writers[i].Items(), "destination %d", i)
+				}
+			}
+		})
+	}
+}
+
+func TestMulticastAsync_SlowDestination(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+
+	slow := &flakyWriter[int]{gate: make(chan struct{})}
+	fast := &flakyWriter[int]{}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = MulticastAsync(MemReader(items, nil), []WriteStream[int]{slow, fast})
+	}()
+
+	assert.Eventually(t, func() bool {
+		return len(fast.Items()) == len(items)
+	}, time.Second, time.Millisecond, "the fast destination must not wait for the slow one")
+
+	close(slow.gate)
+	<-done
+
+	assert.Equal(t, items, slow.Items())
+}
+
+func TestMulticastAsync_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ch := make(chan int)
+	results, err := MulticastAsync(
+		Channel(ch),
+		[]WriteStream[int]{MemWriter[int]()},
+		WithMulticastContext(ctx),
+		WithMulticastBuffer(0),
+	)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []MulticastResult{{}}, results)
+}
+
+func TestMulticastAsync_ReadErrorSkipsFlush(t *testing.T) {
+	errRead := errors.New("read failed")
+	sinks := []*flushCounter[int]{
+		{MemoryWriteStream: MemWriter[int]()},
+		{MemoryWriteStream: MemWriter[int]()},
+	}
+
+	results, err := MulticastAsync(MemReader([]int{1, 2}, errRead), []WriteStream[int]{sinks[0], sinks[1]})
+
+	assert.ErrorIs(t, err, errRead)
+	require.Len(t, results, len(sinks))
+
+	for i, sink := range sinks {
+		assert.Zero(t, sink.flushes, "destination %d", i)
+	}
+}
+
+func TestMulticastAsync_NoDestinations(t *testing.T) {
+	results, err := MulticastAsync[int](MemReader([]int{1}, nil), nil)
+
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+}
+
+// ExampleMulticastAsync demonstrates exporting to several sinks while tolerating a failing one.
+func ExampleMulticastAsync() {
+	archive := MemWriter[string]()
+	broken := MemWriter[string]()
+	broken.SetError(fmt.Errorf("bucket unreachable"))
+
+	results, err := MulticastAsync(
+		MemReader([]string{"a", "b", "c"}, nil),
+		[]WriteStream[string]{archive, broken},
+		WithMulticastPolicy(MulticastDropFailed),
+	)
+
+	fmt.Println("Error:", err)
+	for i, result := range results {
+		fmt.Printf("Destination %d: %d written, err: %v\n", i, result.Bytes, result.Err)
+	}
+	// Output:
+	// Error: <nil>
+	// Destination 0: 3 written, err: <nil>
+	// Destination 1: 0 written, err: write error to destination 1: bucket unreachable
+}
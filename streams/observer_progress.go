@@ -0,0 +1,141 @@
+package streams
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sonirico/vago/clock"
+)
+
+type (
+	// ProgressLogger is the subset of lol.Logger used by ProgressObserver.
+	ProgressLogger interface {
+		Infof(format string, args ...any)
+	}
+
+	progressSeries struct {
+		items  int64
+		bytes  int64
+		errors int64
+	}
+
+	// ProgressObserver is an Observer that periodically logs, for every
+	// observed stream, the totals seen so far and the average throughput.
+	ProgressObserver struct {
+		logger  ProgressLogger
+		clock   clock.Clock
+		start   time.Time
+		mu      sync.Mutex
+		series  map[string]*progressSeries
+		stop    chan struct{}
+		done    chan struct{}
+		stopped sync.Once
+	}
+)
+
+// NewProgressObserver creates a new ProgressObserver logging to logger every
+// interval until Stop is called. A non-positive interval disables periodic
+// logging, leaving it up to calls to Report.
+func NewProgressObserver(logger ProgressLogger, every time.Duration, opts ...ObserveOpt) *ProgressObserver {
+	optsDef := newObserveOpts(opts)
+
+	p := &ProgressObserver{
+		logger: logger,
+		clock:  optsDef.clock,
+		start:  optsDef.clock.Now(),
+		series: make(map[string]*progressSeries),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	if every <= 0 {
+		close(p.done)
+		return p
+	}
+
+	go p.run(every)
+
+	return p
+}
+
+func (p *ProgressObserver) run(every time.Duration) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.Report()
+		case <-p.stop:
+			return
+		}
+	}
+}
+
+func (p *ProgressObserver) get(name string) *progressSeries {
+	s, ok := p.series[name]
+	if !ok {
+		s = new(progressSeries)
+		p.series[name] = s
+	}
+	return s
+}
+
+func (p *ProgressObserver) ObserveItem(name string, bytes int64, _ time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := p.get(name)
+	s.items++
+	s.bytes += bytes
+}
+
+func (p *ProgressObserver) ObserveError(name string, _ error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.get(name).errors++
+}
+
+// Report logs the progress of every observed stream right away, sorted by
+// name.
+func (p *ProgressObserver) Report() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	elapsed := p.clock.Now().Sub(p.start)
+	seconds := elapsed.Seconds()
+
+	names := make([]string, 0, len(p.series))
+	for name := range p.series {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		s := p.series[name]
+
+		var rate float64
+		if seconds > 0 {
+			rate = float64(s.items) / seconds
+		}
+
+		p.logger.Infof("stream %s: %d items, %d bytes, %d errors in %s (%.1f items/s)",
+			name, s.items, s.bytes, s.errors, elapsed.Truncate(time.Millisecond), rate)
+	}
+}
+
+// Stop ends periodic logging and logs a final report. It is safe to call
+// more than once.
+func (p *ProgressObserver) Stop() {
+	p.stopped.Do(func() {
+		close(p.stop)
+		<-p.done
+		p.Report()
+	})
+}
+
+var _ Observer = new(ProgressObserver)
@@ -0,0 +1,201 @@
+package streams
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyBuckets are the upper bounds, in seconds, of the latency
+// histogram exported by PrometheusObserver.
+var DefaultLatencyBuckets = []float64{
+	0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5,
+}
+
+type (
+	prometheusSeries struct {
+		items   int64
+		bytes   int64
+		errors  int64
+		buckets []int64
+		count   int64
+		sum     float64
+	}
+
+	// PrometheusObserver is an Observer that keeps per stream counters and a
+	// latency histogram, and exposes them in the Prometheus text format, so
+	// it can be scraped without pulling the Prometheus client library.
+	//
+	// It implements http.Handler to be mounted on a /metrics endpoint.
+	PrometheusObserver struct {
+		opts   prometheusOpts
+		mu     sync.Mutex
+		series map[string]*prometheusSeries
+	}
+)
+
+// NewPrometheusObserver creates a new PrometheusObserver exporting:
+//
+//   - stream_items_total: items read or written
+//   - stream_bytes_total: bytes written
+//   - stream_errors_total: errors returned
+//   - stream_item_latency_seconds: histogram of per item latencies
+//
+// all labelled with the name of the stream as `stream`.
+func NewPrometheusObserver(opts ...PrometheusOpt) *PrometheusObserver {
+	optsDef := prometheusOpts{
+		buckets: DefaultLatencyBuckets,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	optsDef.buckets = slices.Clone(optsDef.buckets)
+	slices.Sort(optsDef.buckets)
+
+	return &PrometheusObserver{
+		opts:   optsDef,
+		series: make(map[string]*prometheusSeries),
+	}
+}
+
+func (p *PrometheusObserver) get(name string) *prometheusSeries {
+	s, ok := p.series[name]
+	if !ok {
+		s = &prometheusSeries{buckets: make([]int64, len(p.opts.buckets))}
+		p.series[name] = s
+	}
+	return s
+}
+
+func (p *PrometheusObserver) ObserveItem(name string, bytes int64, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	s := p.get(name)
+	s.items++
+	s.bytes += bytes
+	s.count++
+
+	seconds := latency.Seconds()
+	s.sum += seconds
+
+	for i, bound := range p.opts.buckets {
+		if seconds <= bound {
+			s.buckets[i]++
+		}
+	}
+}
+
+func (p *PrometheusObserver) ObserveError(name string, _ error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.get(name).errors++
+}
+
+// WriteTo writes every metric in the Prometheus text exposition format.
+func (p *PrometheusObserver) WriteTo(w io.Writer) (int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	names := make([]string, 0, len(p.series))
+	for name := range p.series {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(*prometheusSeries) int64
+	}{
+		{"stream_items_total", "Items read or written by the stream.", func(s *prometheusSeries) int64 { return s.items }},
+		{"stream_bytes_total", "Bytes written by the stream.", func(s *prometheusSeries) int64 { return s.bytes }},
+		{"stream_errors_total", "Errors returned by the stream.", func(s *prometheusSeries) int64 { return s.errors }},
+	}
+
+	for _, counter := range counters {
+		metric := p.metric(counter.name)
+		fmt.Fprintf(cw, "# HELP %s %s\n# TYPE %s counter\n", metric, counter.help, metric)
+
+		for _, name := range names {
+			fmt.Fprintf(cw, "%s{stream=\"%s\"} %d\n", metric, escapeLabel(name), counter.value(p.series[name]))
+		}
+	}
+
+	metric := p.metric("stream_item_latency_seconds")
+	fmt.Fprintf(cw, "# HELP %s Time spent reading or writing an item.\n# TYPE %s histogram\n", metric, metric)
+
+	for _, name := range names {
+		s := p.series[name]
+		label := escapeLabel(name)
+
+		for i, bound := range p.opts.buckets {
+			fmt.Fprintf(cw, "%s_bucket{stream=\"%s\",le=\"%s\"} %d\n",
+				metric, label, strconv.FormatFloat(bound, 'g', -1, 64), s.buckets[i])
+		}
+
+		fmt.Fprintf(cw, "%s_bucket{stream=\"%s\",le=\"+Inf\"} %d\n", metric, label, s.count)
+		fmt.Fprintf(cw, "%s_sum{stream=\"%s\"} %s\n", metric, label, strconv.FormatFloat(s.sum, 'g', -1, 64))
+		fmt.Fprintf(cw, "%s_count{stream=\"%s\"} %d\n", metric, label, s.count)
+	}
+
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+
+	return cw.n, cw.w.Flush()
+}
+
+// ServeHTTP serves the metrics in the Prometheus text exposition format.
+func (p *PrometheusObserver) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = p.WriteTo(w)
+}
+
+func (p *PrometheusObserver) metric(name string) string {
+	if p.opts.namespace == "" {
+		return name
+	}
+	return p.opts.namespace + "_" + name
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+// countingWriter counts the bytes written and keeps the first error, so a
+// sequence of writes can be checked once.
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
+
+var (
+	_ Observer     = new(PrometheusObserver)
+	_ http.Handler = new(PrometheusObserver)
+)
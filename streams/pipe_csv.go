@@ -26,22 +26,53 @@ type TransformCSV[T csvMarshaler] struct {
 	separator rune
 }
 
+// TransformCSVStruct transforms a stream of structs into a CSV-formatted
+// stream. Items are marshaled with their MarshalCSV method when T or *T
+// implements it, or derived from their `csv` struct tags through a CSVEncoder
+// otherwise.
+type TransformCSVStruct[T any] struct {
+	stream    ReadStream[T]
+	separator rune
+}
+
 // WriteTo writes the transformed stream into an io.Writer in CSV format.
 // It writes the header only once and streams the records as they are marshaled.
 // Returned written result means the actual number of rows written, and not the
 // total bytes. Inner csv writer does not provide that information and it's
 // inefficient to do it here.
-func (p *TransformCSV[T]) WriteTo(w io.Writer) (written int64, err error) {
+func (p *TransformCSV[T]) WriteTo(w io.Writer) (int64, error) {
+	return writeCSV(p.stream, w, p.separator, func(x T) ([]string, []string, error) {
+		return x.MarshalCSV()
+	})
+}
+
+// WriteTo is like TransformCSV.WriteTo. It fails before reading the stream if
+// T neither implements MarshalCSV nor is a struct.
+func (p *TransformCSVStruct[T]) WriteTo(w io.Writer) (int64, error) {
+	marshal, err := csvMarshalFunc[T]()
+	if err != nil {
+		return 0, fmt.Errorf("csv marshaling error: %w", err)
+	}
+
+	return writeCSV(p.stream, w, p.separator, marshal)
+}
+
+func writeCSV[T any](
+	stream ReadStream[T],
+	w io.Writer,
+	separator rune,
+	marshal func(T) ([]string, []string, error),
+) (written int64, err error) {
 	var (
 		headerWritten bool
 	)
 
 	writer := csv.NewWriter(w)
-	writer.Comma = p.separator
+	writer.Comma = separator
 	defer writer.Flush()
 
-	for p.stream.Next() {
-		if err = p.stream.Err(); err != nil {
+	for stream.Next() {
+		if err = stream.Err(); err != nil {
 			if !errors.Is(err, io.EOF) {
 				err = fmt.Errorf("stream err: %w", err)
 			}
@@ -50,9 +81,9 @@ func (p *TransformCSV[T]) WriteTo(w io.Writer) (written int64, err error) {
 
 		var (
 			header, record []string
-			data           = p.stream.Data()
+			data           = stream.Data()
 		)
-		header, record, err = data.MarshalCSV()
+		header, record, err = marshal(data)
 		if err != nil {
 			return written, fmt.Errorf("csv marshaling error: %w", err)
 		}
@@ -74,7 +105,7 @@ func (p *TransformCSV[T]) WriteTo(w io.Writer) (written int64, err error) {
 	}
 
 	// Check for any remaining errors from the stream
-	if err = p.stream.Err(); err != nil {
+	if err = stream.Err(); err != nil {
 		if errors.Is(err, io.EOF) {
 			err = nil
 		} else {
@@ -93,7 +124,22 @@ func CSVTransform[T csvMarshaler](stream ReadStream[T], separator rune) Transfor
 	}
 }
 
+// CSVStructTransform is like CSVTransform for structs that do not need to
+// implement MarshalCSV, see TransformCSVStruct.
+func CSVStructTransform[T any](stream ReadStream[T], separator rune) Transform[T] {
+	return &TransformCSVStruct[T]{
+		stream:    stream,
+		separator: separator,
+	}
+}
+
 // PipeJSONEachRow writes the JSON representation of each row in the stream to the provided writer.
 func PipeCSV[T csvMarshaler](stream ReadStream[T], w io.Writer, writeSep rune) (int64, error) {
 	return CSVTransform(stream, writeSep).WriteTo(w)
 }
+
+// PipeCSVStruct is like PipeCSV for structs that do not need to implement
+// MarshalCSV, see TransformCSVStruct.
+func PipeCSVStruct[T any](stream ReadStream[T], w io.Writer, writeSep rune) (int64, error) {
+	return CSVStructTransform(stream, writeSep).WriteTo(w)
+}
@@ -0,0 +1,241 @@
+package streams
+
+import (
+	"context"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/sonirico/vago/clock"
+)
+
+type (
+	// BatchWindowStream groups items into batches that are flushed by count,
+	// by latency or by estimated size, whichever comes first.
+	//
+	// Items are read from the inner stream by a background goroutine so that
+	// a pending batch can be flushed while the inner stream is blocked, e.g.
+	// a Channel fed by a slow producer.
+	BatchWindowStream[T any] struct {
+		inner      ReadStream[T]
+		batchSize  int
+		maxLatency time.Duration
+		opts       batchWindowOpts[T]
+
+		items      chan T
+		stop       chan struct{}
+		cancel     context.CancelFunc
+		ticks      <-chan time.Time
+		stopTicker func()
+
+		// innerErr is written by the pump before it closes items.
+		innerErr error
+
+		buffer  []T
+		bytes   int
+		first   time.Time
+		err     error
+		started bool
+		done    bool
+		closed  bool
+	}
+)
+
+// BatchWindow creates a new batch-oriented stream like Batch, but a batch is
+// also emitted when `maxLatency` has elapsed since its first item was
+// buffered, so that a half-full batch never waits forever for a slow
+// producer. A zero `maxLatency` disables the time bound.
+//
+// Use WithBatchWindowBytes to flush on an estimated byte size as well, and
+// WithBatchWindowClock and WithBatchWindowTicker to control time in tests.
+func BatchWindow[T any](
+	inner ReadStream[T],
+	batchSize int,
+	maxLatency time.Duration,
+	opts ...BatchWindowOpt[T],
+) ReadStream[[]T] {
+	optsDef := batchWindowOpts[T]{
+		clock: clock.New(),
+		tick:  maxLatency / 10,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	if optsDef.tick <= 0 {
+		optsDef.tick = time.Millisecond
+	}
+
+	return &BatchWindowStream[T]{
+		inner:      inner,
+		batchSize:  batchSize,
+		maxLatency: maxLatency,
+		opts:       optsDef,
+	}
+}
+
+func (s *BatchWindowStream[T]) start() {
+	s.started = true
+	s.items = make(chan T)
+	s.stop = make(chan struct{})
+
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+
+	// Bound to a context of its own, a Channel waiting for a slow producer
+	// can be interrupted when the stream is closed.
+	if aware, ok := s.inner.(contextAware); ok {
+		aware.setContext(ctx)
+	}
+
+	switch {
+	case s.maxLatency <= 0:
+	case s.opts.ticks != nil:
+		s.ticks = s.opts.ticks
+	default:
+		ticker := time.NewTicker(s.opts.tick)
+		s.ticks = ticker.C
+		s.stopTicker = ticker.Stop
+	}
+
+	go s.pump(ctx)
+}
+
+func (s *BatchWindowStream[T]) pump(ctx context.Context) {
+	defer close(s.items)
+
+	for s.inner.Next() {
+		select {
+		case s.items <- s.inner.Data():
+		case <-s.stop:
+			return
+		}
+	}
+
+	// A read aborted by Close is not an error of the inner stream
+	if ctx.Err() == nil {
+		s.innerErr = s.inner.Err()
+	}
+}
+
+func (s *BatchWindowStream[T]) Next() bool {
+	if s.done {
+		return false
+	}
+
+	if !s.started {
+		s.start()
+	}
+
+	// Create a new buffer for each batch instead of reusing
+	s.buffer = make([]T, 0, max(s.batchSize, 0))
+	s.bytes = 0
+
+	for {
+		var tick <-chan time.Time
+		if len(s.buffer) > 0 {
+			tick = s.ticks
+		}
+
+		select {
+		case item, ok := <-s.items:
+			if !ok {
+				s.finish()
+				s.err = s.innerErr
+				return len(s.buffer) > 0
+			}
+
+			if len(s.buffer) == 0 {
+				s.first = s.opts.clock.Now()
+			}
+
+			s.buffer = append(s.buffer, item)
+
+			if s.opts.sizeFn != nil {
+				s.bytes += s.opts.sizeFn(item)
+			}
+
+			if s.full() || s.expired() {
+				return true
+			}
+		case <-tick:
+			if s.expired() {
+				return true
+			}
+		}
+	}
+}
+
+func (s *BatchWindowStream[T]) full() bool {
+	if len(s.buffer) >= s.batchSize {
+		return true
+	}
+
+	return s.opts.sizeFn != nil && s.bytes >= s.opts.maxBytes
+}
+
+func (s *BatchWindowStream[T]) expired() bool {
+	return s.maxLatency > 0 && s.opts.clock.Now().Sub(s.first) >= s.maxLatency
+}
+
+// finish stops the ticker and waits for the pump to exit, so that the inner
+// stream is no longer in use once it returns.
+func (s *BatchWindowStream[T]) finish() {
+	s.done = true
+
+	if s.stopTicker != nil {
+		s.stopTicker()
+	}
+
+	close(s.stop)
+	s.cancel()
+
+	for range s.items {
+	}
+}
+
+func (s *BatchWindowStream[T]) Data() []T {
+	return s.buffer
+}
+
+func (s *BatchWindowStream[T]) Err() error {
+	return s.err
+}
+
+// Close stops the background reader and closes the inner stream. A read
+// pending on an inner stream able to abort it, such as the ones returned by
+// Channel, is interrupted right away; for any other stream Close waits for the
+// pending Next to return before closing it.
+func (s *BatchWindowStream[T]) Close() error {
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	if s.started && !s.done {
+		s.finish()
+	}
+
+	s.done = true
+
+	return s.inner.Close()
+}
+
+func (s *BatchWindowStream[T]) Iter() iter.Seq[[]T] {
+	return Iter(s)
+}
+
+func BatchWindowFactory[T any](
+	innerFactory ReadStreamFactory[T],
+	batchSize int,
+	maxLatency time.Duration,
+	opts ...BatchWindowOpt[T],
+) ReadStreamFactory[[]T] {
+	return func(rc io.ReadCloser) ReadStream[[]T] {
+		return BatchWindow(innerFactory(rc), batchSize, maxLatency, opts...)
+	}
+}
+
+var _ ReadStream[[]any] = new(BatchWindowStream[any])
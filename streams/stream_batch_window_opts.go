@@ -0,0 +1,57 @@
+package streams
+
+import (
+	"time"
+
+	"github.com/sonirico/vago/clock"
+)
+
+type BatchWindowOpt[T any] func(*batchWindowOpts[T])
+
+type batchWindowOpts[T any] struct {
+	clock    clock.Clock
+	tick     time.Duration
+	ticks    <-chan time.Time
+	sizeFn   func(T) int
+	maxBytes int
+}
+
+func (fn BatchWindowOpt[T]) apply(o *batchWindowOpts[T]) {
+	fn(o)
+}
+
+// WithBatchWindowClock sets the clock used to measure batch latency.
+// Use clock.NewMock together with WithBatchWindowTicker to drive the window
+// deterministically in tests.
+func WithBatchWindowClock[T any](c clock.Clock) BatchWindowOpt[T] {
+	return func(o *batchWindowOpts[T]) {
+		o.clock = c
+	}
+}
+
+// WithBatchWindowTick sets how often the latency of the pending batch is
+// checked against the clock. Defaults to a tenth of the max latency.
+func WithBatchWindowTick[T any](d time.Duration) BatchWindowOpt[T] {
+	return func(o *batchWindowOpts[T]) {
+		o.tick = d
+	}
+}
+
+// WithBatchWindowTicker checks the latency of the pending batch whenever ticks
+// delivers a value, instead of on a ticker running every tick. The values
+// themselves are ignored: latency is always measured with the clock.
+func WithBatchWindowTicker[T any](ticks <-chan time.Time) BatchWindowOpt[T] {
+	return func(o *batchWindowOpts[T]) {
+		o.ticks = ticks
+	}
+}
+
+// WithBatchWindowBytes flushes the pending batch once the sum of the sizes
+// estimated by sizeFn reaches maxBytes. The item crossing the threshold is
+// included in the flushed batch.
+func WithBatchWindowBytes[T any](sizeFn func(T) int, maxBytes int) BatchWindowOpt[T] {
+	return func(o *batchWindowOpts[T]) {
+		o.sizeFn = sizeFn
+		o.maxBytes = maxBytes
+	}
+}
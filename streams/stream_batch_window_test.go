@@ -0,0 +1,234 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"testing"
+	"time"
+
+	"github.com/sonirico/vago/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchWindowStream_Count(t *testing.T) {
+	errStream := errors.New("stream error")
+
+	stream := BatchWindow(MemReader([]int{1, 2, 3, 4, 5}, errStream), 2, time.Hour)
+
+	gotBatches := make([][]int, 0)
+	for stream.Next() {
+		gotBatches = append(gotBatches, stream.Data())
+	}
+
+	assert.Equal(t, [][]int{{1, 2}, {3, 4}, {5}}, gotBatches)
+	assert.ErrorIs(t, stream.Err(), errStream)
+	assert.NoError(t, stream.Close())
+}
+
+func TestBatchWindowStream_Latency(t *testing.T) {
+	clk := clock.NewMock(time.Date(2025, 12, 8, 12, 0, 0, 0, time.UTC))
+	ticks := make(chan time.Time)
+
+	// The channel is never closed: only the latency bound can flush
+	ch := make(chan int, 1)
+	ch <- 1
+
+	stream := BatchWindow(
+		Channel(ch),
+		10,
+		time.Second,
+		WithBatchWindowClock[int](clk),
+		WithBatchWindowTicker[int](ticks),
+	)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	// Ticks are only received while a batch is pending, so the clock never
+	// moves before the first item of the batch is buffered
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case ticks <- clk.Now():
+				clk.Add(100 * time.Millisecond)
+			}
+		}
+	}()
+
+	assert.True(t, stream.Next())
+	assert.Equal(t, []int{1}, stream.Data())
+
+	ch <- 2
+	close(ch)
+
+	assert.True(t, stream.Next())
+	assert.Equal(t, []int{2}, stream.Data())
+	assert.False(t, stream.Next())
+	assert.NoError(t, stream.Err())
+}
+
+func TestBatchWindowStream_Bytes(t *testing.T) {
+	stream := BatchWindow(
+		MemReader([]string{"ab", "cd", "ef", "g"}, nil),
+		10,
+		0,
+		WithBatchWindowBytes(func(s string) int { return len(s) }, 5),
+	)
+
+	result, err := Consume(stream)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"ab", "cd", "ef"}, {"g"}}, result)
+}
+
+func TestBatchWindowStream_CloseStopsPump(t *testing.T) {
+	ch := make(chan int)
+
+	go func() {
+		for i := 0; i < 10; i++ {
+			ch <- i
+		}
+		close(ch)
+	}()
+
+	stream := BatchWindow(Channel(ch), 2, time.Hour)
+
+	assert.True(t, stream.Next())
+	assert.Equal(t, []int{0, 1}, stream.Data())
+	assert.NoError(t, stream.Close())
+	assert.False(t, stream.Next())
+}
+
+func TestBatchWindowStream_CloseIdleChannel(t *testing.T) {
+	ch := make(chan int)
+	inner := track(Channel(ch))
+
+	stream := BatchWindow[int](inner, 2, time.Hour)
+
+	go func() {
+		ch <- 1
+		ch <- 2
+	}()
+
+	assert.True(t, stream.Next())
+	assert.Equal(t, []int{1, 2}, stream.Data())
+
+	// Nothing else is ever sent: the pump stays blocked in the inner Next
+	closed := make(chan error)
+	go func() { closed <- stream.Close() }()
+
+	select {
+	case err := <-closed:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close hung on an idle channel")
+	}
+
+	assert.True(t, inner.closed)
+	assert.False(t, stream.Next())
+	assert.NoError(t, stream.Err())
+}
+
+// blockingStream reads from a channel without being able to abort the read,
+// and is not safe for concurrent use.
+type blockingStream struct {
+	items   chan int
+	current int
+	reading bool
+	closed  bool
+}
+
+func (s *blockingStream) Next() bool {
+	s.reading = true
+	defer func() { s.reading = false }()
+
+	var ok bool
+	s.current, ok = <-s.items
+	return ok
+}
+
+func (s *blockingStream) Data() int {
+	return s.current
+}
+
+func (s *blockingStream) Err() error {
+	return nil
+}
+
+func (s *blockingStream) Close() error {
+	if s.reading {
+		return errors.New("closed during Next")
+	}
+
+	s.closed = true
+	return nil
+}
+
+func (s *blockingStream) Iter() iter.Seq[int] {
+	return Iter(s)
+}
+
+func TestBatchWindowStream_CloseWaitsForPump(t *testing.T) {
+	inner := &blockingStream{items: make(chan int, 1)}
+	inner.items <- 1
+
+	stream := BatchWindow[int](inner, 1, time.Hour)
+
+	assert.True(t, stream.Next())
+	assert.Equal(t, []int{1}, stream.Data())
+
+	// The pump is now blocked in the inner Next, which cannot be interrupted
+	closed := make(chan error)
+	go func() { closed <- stream.Close() }()
+
+	select {
+	case <-closed:
+		t.Fatal("Close returned while the inner Next was pending")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(inner.items)
+
+	assert.NoError(t, <-closed)
+	assert.True(t, inner.closed)
+}
+
+func TestBatchWindowStream_ChannelContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+
+	stream := BatchWindow(ChannelContext(ctx, ch), 2, time.Hour)
+
+	go func() {
+		ch <- 1
+		cancel()
+	}()
+
+	assert.True(t, stream.Next())
+	assert.Equal(t, []int{1}, stream.Data())
+	assert.False(t, stream.Next())
+	assert.ErrorIs(t, stream.Err(), context.Canceled)
+	assert.NoError(t, stream.Close())
+}
+
+// ExampleBatchWindow demonstrates batching with a count and a latency bound.
+func ExampleBatchWindow() {
+	// Create a stream from a slice of integers
+	stream := MemReader([]int{1, 2, 3, 4, 5, 6, 7}, nil)
+
+	// Emit batches of 3 items, or whatever is buffered after 100ms
+	batchStream := BatchWindow(stream, 3, 100*time.Millisecond)
+
+	// Collect the results
+	result, _ := Consume(batchStream)
+	for i, batch := range result {
+		fmt.Printf("Batch %d: %v\n", i+1, batch)
+	}
+	// Output:
+	// Batch 1: [1 2 3]
+	// Batch 2: [4 5 6]
+	// Batch 3: [7]
+}
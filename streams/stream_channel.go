@@ -1,22 +1,55 @@
 package streams
 
-import "iter"
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+)
 
 type StreamChannel[T any] struct {
+	ctx     context.Context
 	ch      <-chan T
 	current T
+	err     error
+
+	// release unregisters the contexts bound with setContext
+	release []func()
 }
 
 func Channel[T any](ch <-chan T) ReadStream[T] {
+	return ChannelContext(context.Background(), ch)
+}
+
+// ChannelContext creates a new ReadStream that receives from ch until it is
+// closed or ctx is done. A pending receive is interrupted as soon as ctx is
+// done, in which case Err returns ctx.Err().
+func ChannelContext[T any](ctx context.Context, ch <-chan T) ReadStream[T] {
 	return &StreamChannel[T]{
-		ch: ch,
+		ctx: ctx,
+		ch:  ch,
 	}
 }
 
 func (s *StreamChannel[T]) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	if s.ctx.Err() != nil {
+		s.err = context.Cause(s.ctx)
+		return false
+	}
+
 	var ok bool
-	s.current, ok = <-s.ch
-	return ok
+
+	select {
+	case s.current, ok = <-s.ch:
+		return ok
+	case <-s.ctx.Done():
+		s.err = context.Cause(s.ctx)
+		return false
+	}
 }
 
 func (s *StreamChannel[T]) Data() T {
@@ -24,10 +57,15 @@ func (s *StreamChannel[T]) Data() T {
 }
 
 func (s *StreamChannel[T]) Err() error {
-	return nil
+	return s.err
 }
 
 func (s *StreamChannel[T]) Close() error {
+	for _, release := range s.release {
+		release()
+	}
+	s.release = nil
+
 	return nil
 }
 
@@ -35,4 +73,70 @@ func (s *StreamChannel[T]) Iter() iter.Seq[T] {
 	return Iter(s)
 }
 
+// setContext binds ctx on top of the context the stream already honours, so
+// receives are interrupted as soon as either of them is done. Err reports the
+// error of the one done first.
+func (s *StreamChannel[T]) setContext(ctx context.Context) {
+	merged, cancel := context.WithCancelCause(s.ctx)
+	stop := context.AfterFunc(ctx, func() {
+		cancel(ctx.Err())
+	})
+
+	s.ctx = merged
+	s.release = append(s.release, func() {
+		stop()
+		cancel(nil)
+	})
+}
+
+// ToChannel reads stream from a new goroutine and sends its items to the
+// returned data channel, which has the given buffer size, so streams can feed
+// select-based code such as worker pools. The goroutine owns the stream and
+// closes it once exhausted.
+//
+// The error channel receives at most one error, the read error of the stream,
+// ctx.Err() when ctx is done first, or the error closing the stream. Both
+// channels are closed once the goroutine exits, so consumers can range over
+// the data channel and then receive from the error channel.
+func ToChannel[T any](ctx context.Context, stream ReadStream[T], buffer int) (<-chan T, <-chan error) {
+	if buffer < 0 {
+		buffer = 0
+	}
+
+	data := make(chan T, buffer)
+	errs := make(chan error, 1)
+
+	if aware, ok := stream.(contextAware); ok {
+		aware.setContext(ctx)
+	}
+
+	go func() {
+		defer close(errs)
+		defer close(data)
+
+		var err error
+
+	loop:
+		for stream.Next() {
+			select {
+			case data <- stream.Data():
+			case <-ctx.Done():
+				break loop
+			}
+		}
+
+		if err = ctx.Err(); err == nil {
+			if err = stream.Err(); errors.Is(err, io.EOF) {
+				err = nil
+			}
+		}
+
+		if err = errors.Join(err, stream.Close()); err != nil {
+			errs <- err
+		}
+	}()
+
+	return data, errs
+}
+
 var _ ReadStream[any] = new(StreamChannel[any])
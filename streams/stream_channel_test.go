@@ -1,8 +1,14 @@
 package streams
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestStreamChannel(t *testing.T) {
@@ -86,3 +92,107 @@ func ExampleChannel() {
 	// 20
 	// 30
 }
+
+func TestToChannel(t *testing.T) {
+	errRead := errors.New("read failed")
+
+	tests := []struct {
+		name        string
+		stream      ReadStream[int]
+		buffer      int
+		expected    []int
+		expectedErr error
+	}{
+		{
+			name:     "Unbuffered",
+			stream:   MemReader([]int{1, 2, 3}, nil),
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "Buffered",
+			stream:   MemReader([]int{1, 2, 3}, nil),
+			buffer:   8,
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:        "Read error",
+			stream:      MemReader([]int{1}, errRead),
+			expected:    []int{1},
+			expectedErr: errRead,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tracked := track(tc.stream)
+			data, errs := ToChannel[int](context.Background(), tracked, tc.buffer)
+
+			var got []int
+			for v := range data {
+				got = append(got, v)
+			}
+
+			assert.Equal(t, tc.expected, got)
+			assert.ErrorIs(t, <-errs, tc.expectedErr)
+			assert.True(t, tracked.closed)
+		})
+	}
+}
+
+func TestToChannel_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// the source never ends, so only the context can stop the goroutine
+	data, errs := ToChannel(ctx, Channel(make(chan int)), 0)
+	cancel()
+
+	_, ok := <-data
+	assert.False(t, ok)
+	assert.ErrorIs(t, <-errs, context.Canceled)
+}
+
+func TestToChannel_WorkerPool(t *testing.T) {
+	items := make([]int, 100)
+	for i := range items {
+		items[i] = i + 1
+	}
+
+	data, errs := ToChannel(context.Background(), MemReader(items, nil), 4)
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sum int
+	)
+
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for v := range data {
+				mu.Lock()
+				sum += v
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+	require.NoError(t, <-errs)
+	assert.Equal(t, 5050, sum)
+}
+
+// ExampleToChannel demonstrates feeding a stream into select-based code.
+func ExampleToChannel() {
+	data, errs := ToChannel(context.Background(), MemReader([]string{"job-1", "job-2"}, nil), 1)
+
+	for job := range data {
+		fmt.Println("processing", job)
+	}
+
+	fmt.Println("error:", <-errs)
+	// Output:
+	// processing job-1
+	// processing job-2
+	// error: <nil>
+}
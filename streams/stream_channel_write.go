@@ -0,0 +1,100 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrChannelSendTimeout  = errors.New("channel send timeout")
+	ErrChannelWriterClosed = errors.New("channel writer closed")
+)
+
+// ChannelWriteStream is a WriteStream that sends every item to a channel,
+// blocking while the channel is full so slow consumers apply backpressure.
+type ChannelWriteStream[T any] struct {
+	ch     chan<- T
+	opts   channelWriterOpts
+	err    error
+	closed bool
+}
+
+// ChannelWriter creates a new WriteStream sending to ch. Sends wait for the
+// channel to accept the item, unless the context given with
+// WithChannelWriterContext is done or the WithChannelWriterTimeout elapses
+// first, in which case the write fails and the stream stays failed.
+func ChannelWriter[T any](ch chan<- T, opts ...ChannelWriterOpt) *ChannelWriteStream[T] {
+	optsDef := channelWriterOpts{
+		ctx: context.Background(),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	return &ChannelWriteStream[T]{
+		ch:   ch,
+		opts: optsDef,
+	}
+}
+
+// Write sends item to the channel and returns 1 (one item written)
+func (w *ChannelWriteStream[T]) Write(item T) (int64, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	if w.closed {
+		return 0, ErrChannelWriterClosed
+	}
+
+	if w.err = w.opts.ctx.Err(); w.err != nil {
+		return 0, w.err
+	}
+
+	var timeout <-chan time.Time
+	if w.opts.timeout > 0 {
+		timer := time.NewTimer(w.opts.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case w.ch <- item:
+		return 1, nil
+	case <-w.opts.ctx.Done():
+		w.err = w.opts.ctx.Err()
+	case <-timeout:
+		w.err = ErrChannelSendTimeout
+	}
+
+	return 0, w.err
+}
+
+// Flush is a no-op since items are handed over as soon as they are written
+func (w *ChannelWriteStream[T]) Flush() error {
+	return w.err
+}
+
+// Err returns the current error state of the stream
+func (w *ChannelWriteStream[T]) Err() error {
+	return w.err
+}
+
+// Close closes the channel when WithChannelWriterClose was given
+func (w *ChannelWriteStream[T]) Close() error {
+	if w.closed {
+		return w.err
+	}
+
+	w.closed = true
+
+	if w.opts.close {
+		close(w.ch)
+	}
+
+	return w.err
+}
+
+var _ WriteStream[any] = new(ChannelWriteStream[any])
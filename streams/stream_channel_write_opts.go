@@ -0,0 +1,43 @@
+package streams
+
+import (
+	"context"
+	"time"
+)
+
+type ChannelWriterOpt func(*channelWriterOpts)
+
+type channelWriterOpts struct {
+	ctx     context.Context
+	timeout time.Duration
+	close   bool
+}
+
+func (fn ChannelWriterOpt) apply(o *channelWriterOpts) {
+	fn(o)
+}
+
+// WithChannelWriterContext aborts a pending send once ctx is done, reporting
+// ctx.Err() as the write error.
+func WithChannelWriterContext(ctx context.Context) ChannelWriterOpt {
+	return func(o *channelWriterOpts) {
+		o.ctx = ctx
+	}
+}
+
+// WithChannelWriterTimeout fails a send that is not accepted by the channel
+// within d with ErrChannelSendTimeout. Defaults to waiting forever.
+func WithChannelWriterTimeout(d time.Duration) ChannelWriterOpt {
+	return func(o *channelWriterOpts) {
+		o.timeout = d
+	}
+}
+
+// WithChannelWriterClose makes Close close the channel, signalling consumers
+// that no more items will arrive. Only use it when the writer is the only
+// sender.
+func WithChannelWriterClose() ChannelWriterOpt {
+	return func(o *channelWriterOpts) {
+		o.close = true
+	}
+}
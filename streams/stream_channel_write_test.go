@@ -0,0 +1,68 @@
+package streams
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelWriter(t *testing.T) {
+	t.Run("Sends and closes", func(t *testing.T) {
+		ch := make(chan string, 2)
+		w := ChannelWriter(ch, WithChannelWriterClose())
+
+		written, err := WriteAll[string](w, []string{"a", "b"})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), written)
+		require.NoError(t, w.Close())
+
+		result, err := Consume(Channel(ch))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, result)
+
+		_, err = w.Write("c")
+		assert.ErrorIs(t, err, ErrChannelWriterClosed)
+	})
+
+	t.Run("Send timeout", func(t *testing.T) {
+		w := ChannelWriter(make(chan int), WithChannelWriterTimeout(5*time.Millisecond))
+
+		_, err := w.Write(1)
+		assert.ErrorIs(t, err, ErrChannelSendTimeout)
+		assert.ErrorIs(t, w.Err(), ErrChannelSendTimeout)
+
+		_, err = w.Write(2)
+		assert.ErrorIs(t, err, ErrChannelSendTimeout, "the stream stays failed")
+	})
+
+	t.Run("Context cancellation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		w := ChannelWriter(make(chan int), WithChannelWriterContext(ctx))
+
+		go func() {
+			time.Sleep(5 * time.Millisecond)
+			cancel()
+		}()
+
+		_, err := w.Write(1)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, w.Flush(), context.Canceled)
+	})
+
+	t.Run("Pipe into a channel", func(t *testing.T) {
+		ch := make(chan int)
+		w := ChannelWriter(ch, WithChannelWriterClose())
+
+		go func() {
+			_, _ = Pipe(MemReader([]int{1, 2, 3}, nil), w)
+			_ = w.Close()
+		}()
+
+		result, err := Consume(Channel(ch))
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, result)
+	})
+}
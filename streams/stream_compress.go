@@ -0,0 +1,245 @@
+package streams
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+)
+
+// Compression is a compression format that stream factories can be
+// decorated with. Gzip is built in as GzipCompression; other formats, such as
+// zstd in the compress module, are supplied by the caller so the root module
+// does not depend on their codecs.
+type Compression interface {
+	// Magic returns the bytes every compressed input starts with, which
+	// Decompress uses to detect the format.
+	Magic() []byte
+	// NewReader returns a reader decompressing r.
+	NewReader(r io.Reader) (io.ReadCloser, error)
+	// NewWriter returns a writer compressing into w at the given level, or at
+	// the default level of the format when level is nil.
+	NewWriter(w io.Writer, level *int) (Compressor, error)
+}
+
+// Compressor is a compressing writer that can flush what it has buffered.
+type Compressor interface {
+	io.WriteCloser
+	Flush() error
+}
+
+// GzipCompression is the gzip Compression of the standard library.
+var GzipCompression Compression = gzipCompression{}
+
+type gzipCompression struct{}
+
+func (gzipCompression) Magic() []byte {
+	return []byte{0x1f, 0x8b}
+}
+
+func (gzipCompression) NewReader(r io.Reader) (io.ReadCloser, error) {
+	dec, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return dec, nil
+}
+
+func (gzipCompression) NewWriter(w io.Writer, level *int) (Compressor, error) {
+	if level == nil {
+		return gzip.NewWriter(w), nil
+	}
+
+	enc, err := gzip.NewWriterLevel(w, *level)
+	if err != nil {
+		return nil, err
+	}
+
+	return enc, nil
+}
+
+// Gzip decorates a ReadStreamFactory so it reads gzip compressed input.
+// Closing the stream closes the decompressor and then the underlying reader.
+// For zstd input use compress.ZstdReader, from the compress module.
+func Gzip[T any](factory ReadStreamFactory[T]) ReadStreamFactory[T] {
+	return DecompressWith(GzipCompression, factory)
+}
+
+// DecompressWith decorates a ReadStreamFactory so it reads input compressed
+// in the format of c. Closing the stream closes the decompressor and then the
+// underlying reader.
+func DecompressWith[T any](c Compression, factory ReadStreamFactory[T]) ReadStreamFactory[T] {
+	return func(rc io.ReadCloser) ReadStream[T] {
+		return factory(newDecompressReader(rc, func(r *bufio.Reader) (io.ReadCloser, error) {
+			return c.NewReader(r)
+		}))
+	}
+}
+
+// Decompress decorates a ReadStreamFactory so it reads gzip compressed input,
+// or input in any of the given formats, detected by its magic bytes. Input in
+// any other format is passed through unchanged.
+func Decompress[T any](factory ReadStreamFactory[T], formats ...Compression) ReadStreamFactory[T] {
+	formats = append([]Compression{GzipCompression}, formats...)
+
+	return func(rc io.ReadCloser) ReadStream[T] {
+		return factory(newDecompressReader(rc, func(r *bufio.Reader) (io.ReadCloser, error) {
+			return openDetect(r, formats)
+		}))
+	}
+}
+
+// GzipWriter decorates a WriteStreamFactory so it writes gzip compressed
+// output. Closing the stream flushes and closes the compressor before the
+// underlying writer, so the trailer is never lost. For zstd output use
+// compress.ZstdWriter, from the compress module.
+func GzipWriter[T any](factory WriteStreamFactory[T], opts ...CompressOpt) WriteStreamFactory[T] {
+	return CompressWith(GzipCompression, factory, opts...)
+}
+
+// CompressWith decorates a WriteStreamFactory so it writes output compressed
+// in the format of c. Closing the stream flushes and closes the compressor
+// before the underlying writer, so the last block is never lost.
+func CompressWith[T any](
+	c Compression,
+	factory WriteStreamFactory[T],
+	opts ...CompressOpt,
+) WriteStreamFactory[T] {
+	optsDef := compressOpts{}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	return func(wc io.WriteCloser) WriteStream[T] {
+		enc, err := c.NewWriter(wc, optsDef.level)
+		return factory(newCompressWriter(wc, enc, err))
+	}
+}
+
+// openDecompressor wraps r with a decompressor.
+type openDecompressor func(r *bufio.Reader) (io.ReadCloser, error)
+
+func openDetect(r *bufio.Reader, formats []Compression) (io.ReadCloser, error) {
+	var size int
+	for _, c := range formats {
+		size = max(size, len(c.Magic()))
+	}
+
+	magic, err := r.Peek(size)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	for _, c := range formats {
+		if bytes.HasPrefix(magic, c.Magic()) {
+			return c.NewReader(r)
+		}
+	}
+
+	return io.NopCloser(r), nil
+}
+
+// decompressReader opens its decompressor on the first Read, so building a
+// stream never blocks on its input.
+type decompressReader struct {
+	src     io.ReadCloser
+	open    openDecompressor
+	r       io.Reader
+	release func() error
+	err     error
+}
+
+func newDecompressReader(src io.ReadCloser, open openDecompressor) *decompressReader {
+	return &decompressReader{
+		src:  src,
+		open: open,
+	}
+}
+
+func (d *decompressReader) Read(p []byte) (int, error) {
+	if d.r == nil && d.err == nil {
+		var dec io.ReadCloser
+		if dec, d.err = d.open(bufio.NewReader(d.src)); d.err == nil {
+			d.r, d.release = dec, dec.Close
+		}
+	}
+
+	if d.err != nil {
+		return 0, d.err
+	}
+
+	return d.r.Read(p)
+}
+
+// Close releases the decompressor and then closes the underlying reader.
+func (d *decompressReader) Close() error {
+	var err error
+	if d.release != nil {
+		err = d.release()
+		d.release = nil
+	}
+
+	return errors.Join(err, d.src.Close())
+}
+
+// compressWriter routes writes through a compressor and owns the close
+// ordering between the compressor and the underlying writer.
+type compressWriter struct {
+	dst    io.WriteCloser
+	enc    Compressor
+	err    error
+	closed bool
+}
+
+func newCompressWriter(dst io.WriteCloser, enc Compressor, err error) *compressWriter {
+	return &compressWriter{
+		dst: dst,
+		enc: enc,
+		err: err,
+	}
+}
+
+func (c *compressWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+
+	return c.enc.Write(p)
+}
+
+// Flush flushes the compressor and then the underlying writer, if it can be
+// flushed, so everything written so far can be decompressed.
+func (c *compressWriter) Flush() error {
+	if c.err != nil {
+		return c.err
+	}
+
+	if err := c.enc.Flush(); err != nil {
+		return err
+	}
+
+	if flusher, ok := c.dst.(interface{ Flush() error }); ok {
+		return flusher.Flush()
+	}
+
+	return nil
+}
+
+// Close closes the compressor, writing its trailer, and then the underlying
+// writer. The underlying writer is closed even if the compressor fails.
+func (c *compressWriter) Close() error {
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+
+	err := c.err
+	if err == nil {
+		err = c.enc.Close()
+	}
+
+	return errors.Join(err, c.dst.Close())
+}
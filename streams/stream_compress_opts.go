@@ -0,0 +1,20 @@
+package streams
+
+type CompressOpt func(*compressOpts)
+
+type compressOpts struct {
+	level *int
+}
+
+func (fn CompressOpt) apply(o *compressOpts) {
+	fn(o)
+}
+
+// WithCompressLevel sets the compression level, from 0 (no compression) to 9
+// (best compression) for gzip. Other formats define their own range. Defaults
+// to the default level of each format.
+func WithCompressLevel(level int) CompressOpt {
+	return func(o *compressOpts) {
+		o.level = &level
+	}
+}
@@ -0,0 +1,281 @@
+package streams
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// closeRecorder is an io.WriteCloser that rejects writes once closed, so
+// writing a compressor trailer after the file is closed fails loudly.
+type closeRecorder struct {
+	bytes.Buffer
+	closed bool
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
+
+func (c *closeRecorder) Write(p []byte) (int, error) {
+	if c.closed {
+		return 0, errors.New("write after close")
+	}
+	return c.Buffer.Write(p)
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
+
+// readCloseRecorder tracks whether the underlying reader was closed.
+type readCloseRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *readCloseRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func linesFactory(rc io.ReadCloser) ReadStream[string] {
+	return Lines(rc)
+}
+
+func bytesWriterFactory(wc io.WriteCloser) WriteStream[[]byte] {
+	return Writer(wc)
+}
+
+func gzipBytes(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// zlibCompression is a caller supplied Compression, matching zlib streams
+// written with the default level.
+type zlibCompression struct{}
+
+func (zlibCompression) Magic() []byte {
+	return []byte{0x78, 0x9c}
+}
+
+func (zlibCompression) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return zlib.NewReader(r)
+}
+
+func (zlibCompression) NewWriter(w io.Writer, level *int) (Compressor, error) {
+	if level == nil {
+		return zlib.NewWriter(w), nil
+	}
+
+	return zlib.NewWriterLevel(w, *level)
+}
+
+func zlibBytes(t *testing.T, data string) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, err := w.Write([]byte(data))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestCompress_Read(t *testing.T) {
+	const text = "first\nsecond\nthird\n"
+
+	tests := []struct {
+		name        string
+		factory     ReadStreamFactory[string]
+		input       []byte
+		expected    []string
+		errContains string
+	}{
+		{
+			name:     "Gzip",
+			factory:  Gzip(linesFactory),
+			input:    gzipBytes(t, text),
+			expected: []string{"first", "second", "third"},
+		},
+		{
+			name:     "Custom",
+			factory:  DecompressWith(zlibCompression{}, linesFactory),
+			input:    zlibBytes(t, text),
+			expected: []string{"first", "second", "third"},
+		},
+		{
+			name:     "Detect gzip",
+			factory:  Decompress(linesFactory),
+			input:    gzipBytes(t, text),
+			expected: []string{"first", "second", "third"},
+		},
+		{
+			name:     "Detect custom",
+			factory:  Decompress(linesFactory, zlibCompression{}),
+			input:    zlibBytes(t, text),
+			expected: []string{"first", "second", "third"},
+		},
+		{
+			name:     "Detect plain",
+			factory:  Decompress(linesFactory),
+			input:    []byte(text),
+			expected: []string{"first", "second", "third"},
+		},
+		{
+			name:     "Detect short input",
+			factory:  Decompress(linesFactory),
+			input:    []byte("a"),
+			expected: []string{"a"},
+		},
+		{
+			name:     "Detect empty input",
+			factory:  Decompress(linesFactory),
+			input:    nil,
+			expected: nil,
+		},
+		{
+			name:        "Gzip on plain input",
+			factory:     Gzip(linesFactory),
+			input:       []byte(text),
+			errContains: "gzip: invalid header",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			src := &readCloseRecorder{Reader: bytes.NewReader(tc.input)}
+			stream := tc.factory(src)
+
+			var got []string
+			for stream.Next() {
+				got = append(got, stream.Data())
+			}
+
+			if tc.errContains != "" {
+				assert.ErrorContains(t, stream.Err(), tc.errContains)
+			} else {
+				assert.NoError(t, stream.Err())
+				assert.Equal(t, tc.expected, got)
+			}
+
+			assert.NoError(t, stream.Close())
+			assert.True(t, src.closed)
+		})
+	}
+}
+
+func TestCompress_Write(t *testing.T) {
+	tests := []struct {
+		name       string
+		factory    WriteStreamFactory[[]byte]
+		decompress ReadStreamFactory[string]
+	}{
+		{
+			name:       "Gzip",
+			factory:    GzipWriter(bytesWriterFactory),
+			decompress: Gzip(linesFactory),
+		},
+		{
+			name:       "Gzip best compression",
+			factory:    GzipWriter(bytesWriterFactory, WithCompressLevel(gzip.BestCompression)),
+			decompress: Decompress(linesFactory),
+		},
+		{
+			name:       "Custom",
+			factory:    CompressWith(zlibCompression{}, bytesWriterFactory),
+			decompress: Decompress(linesFactory, zlibCompression{}),
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := &closeRecorder{}
+			stream := tc.factory(dst)
+
+			_, err := WriteAll(stream, [][]byte{[]byte("a\n"), []byte("b\n")})
+			require.NoError(t, err)
+			require.NoError(t, stream.Flush())
+			require.NoError(t, stream.Close())
+			assert.True(t, dst.closed)
+
+			result, err := Consume(tc.decompress(io.NopCloser(&dst.Buffer)))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"a", "b"}, result)
+		})
+	}
+}
+
+func TestCompress_WriteNoCompression(t *testing.T) {
+	text := []byte(strings.Repeat("stored as is\n", 32))
+
+	var buf bytes.Buffer
+	stream := GzipWriter(bytesWriterFactory, WithCompressLevel(gzip.NoCompression))(nopWriteCloser{&buf})
+
+	_, err := stream.Write(text)
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+
+	assert.True(t, bytes.Contains(buf.Bytes(), text), "level 0 must store the input uncompressed")
+	assert.Less(t, len(gzipBytes(t, string(text))), len(text), "the default level must compress")
+}
+
+func TestCompress_WriteInvalidLevel(t *testing.T) {
+	dst := &closeRecorder{}
+	stream := GzipWriter(bytesWriterFactory, WithCompressLevel(42))(dst)
+
+	_, err := stream.Write([]byte("a"))
+	assert.ErrorContains(t, err, "invalid compression level")
+
+	enc, err := gzip.NewWriterLevel(dst, 42)
+	w := newCompressWriter(dst, enc, err)
+
+	assert.Error(t, w.Close())
+	assert.True(t, dst.closed, "the file must be closed even if the compressor failed")
+}
+
+// ExampleDecompress demonstrates reading compressed and plain input with the same factory.
+func ExampleDecompress() {
+	var compressed bytes.Buffer
+
+	write := GzipWriter(func(wc io.WriteCloser) WriteStream[[]byte] {
+		return Writer(wc)
+	})
+
+	w := write(nopWriteCloser{&compressed})
+	_, _ = w.Write([]byte("alpha\nbeta\n"))
+	_ = w.Close()
+
+	read := Decompress(func(rc io.ReadCloser) ReadStream[string] {
+		return Lines(rc)
+	})
+
+	inputs := []io.Reader{&compressed, strings.NewReader("gamma\n")}
+	for _, input := range inputs {
+		stream := read(io.NopCloser(input))
+		for stream.Next() {
+			fmt.Println(stream.Data())
+		}
+		_ = stream.Close()
+	}
+	// Output:
+	// alpha
+	// beta
+	// gamma
+}
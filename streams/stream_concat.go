@@ -0,0 +1,70 @@
+package streams
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+// ConcatStream reads its inner streams one after the other.
+type ConcatStream[T any] struct {
+	streams []ReadStream[T]
+	index   int
+	current T
+	err     error
+}
+
+// Concat creates a new ReadStream that yields every item of the first stream,
+// then every item of the second one, and so on. The first inner error stops
+// the stream and is reported by Err; io.EOF, as reported by JSON streams,
+// only ends its own stream. Close closes all the inner streams.
+func Concat[T any](streams ...ReadStream[T]) ReadStream[T] {
+	return &ConcatStream[T]{
+		streams: streams,
+	}
+}
+
+func (s *ConcatStream[T]) Next() bool {
+	for s.err == nil && s.index < len(s.streams) {
+		stream := s.streams[s.index]
+
+		if stream.Next() {
+			s.current = stream.Data()
+			return true
+		}
+
+		if err := stream.Err(); !errors.Is(err, io.EOF) {
+			s.err = err
+		}
+		s.index++
+	}
+
+	return false
+}
+
+func (s *ConcatStream[T]) Data() T {
+	return s.current
+}
+
+func (s *ConcatStream[T]) Err() error {
+	return s.err
+}
+
+func (s *ConcatStream[T]) Close() error {
+	errs := make([]error, len(s.streams))
+	for i, stream := range s.streams {
+		errs[i] = stream.Close()
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *ConcatStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *ConcatStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[any] = new(ConcatStream[any])
@@ -0,0 +1,132 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trackedStream records whether the wrapped stream was closed.
+type trackedStream[T any] struct {
+	ReadStream[T]
+	closed   bool
+	closeErr error
+}
+
+func track[T any](inner ReadStream[T]) *trackedStream[T] {
+	return &trackedStream[T]{ReadStream: inner}
+}
+
+func (s *trackedStream[T]) Close() error {
+	s.closed = true
+	return s.closeErr
+}
+
+func (s *trackedStream[T]) setContext(ctx context.Context) {
+	if inner, ok := s.ReadStream.(contextAware); ok {
+		inner.setContext(ctx)
+	}
+}
+
+func TestConcatStream(t *testing.T) {
+	errRead := errors.New("read failed")
+
+	tests := []struct {
+		name        string
+		streams     []ReadStream[int]
+		expected    []int
+		expectedErr error
+	}{
+		{
+			name:     "No streams",
+			expected: nil,
+		},
+		{
+			name: "Several streams",
+			streams: []ReadStream[int]{
+				MemReader([]int{1, 2}, nil),
+				MemReader([]int{}, nil),
+				MemReader([]int{3}, nil),
+			},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name: "Error stops the stream",
+			streams: []ReadStream[int]{
+				MemReader([]int{1}, nil),
+				MemReader([]int{2}, errRead),
+				MemReader([]int{3}, nil),
+			},
+			expected:    []int{1, 2},
+			expectedErr: errRead,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Concat(tc.streams...)
+
+			var got []int
+			for s.Next() {
+				got = append(got, s.Data())
+			}
+
+			assert.Equal(t, tc.expected, got)
+			assert.ErrorIs(t, s.Err(), tc.expectedErr)
+			assert.False(t, s.Next())
+		})
+	}
+}
+
+// jsonRows returns a JSON stream, which reports io.EOF through Err once the
+// input is exhausted.
+func jsonRows[T any](input string) ReadStream[T] {
+	return JSON[T](io.NopCloser(strings.NewReader(input)))
+}
+
+func TestConcatStream_JSON(t *testing.T) {
+	s := Concat(jsonRows[int]("1\n2\n"), jsonRows[int]("3\n"), jsonRows[int]("4\n"))
+
+	result, err := Consume(s)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, result)
+	assert.NoError(t, s.Err())
+}
+
+func TestConcatStream_Close(t *testing.T) {
+	errClose := errors.New("close failed")
+
+	a := track[int](MemReader([]int{1}, nil))
+	b := track[int](MemReader([]int{2}, nil))
+	b.closeErr = errClose
+
+	s := Concat[int](a, b)
+	require.True(t, s.Next())
+
+	assert.ErrorIs(t, s.Close(), errClose)
+	assert.True(t, a.closed)
+	assert.True(t, b.closed)
+}
+
+// ExampleConcat demonstrates reading several streams one after the other.
+func ExampleConcat() {
+	january := MemReader([]string{"jan-1", "jan-2"}, nil)
+	february := MemReader([]string{"feb-1"}, nil)
+
+	stream := Concat[string](january, february)
+	defer stream.Close()
+
+	for stream.Next() {
+		fmt.Println(stream.Data())
+	}
+	// Output:
+	// jan-1
+	// jan-2
+	// feb-1
+}
@@ -0,0 +1,84 @@
+package streams
+
+import (
+	"context"
+	"iter"
+)
+
+type (
+	// contextAware is implemented by streams that can abort a blocking Next
+	// call when their context is done, such as StreamChannel. setContext adds
+	// ctx to the contexts the stream already honours rather than replacing
+	// them.
+	contextAware interface {
+		setContext(ctx context.Context)
+	}
+
+	// ContextStream stops the inner stream as soon as a context is done.
+	ContextStream[T any] struct {
+		ctx   context.Context
+		inner ReadStream[T]
+		err   error
+	}
+)
+
+// WithContext wraps a ReadStream so that Next returns false as soon as ctx is
+// done, and Err reports ctx.Err(). Streams able to abort a blocking read, such
+// as the one returned by Channel, are bound to ctx as well, so a pending
+// receive is interrupted too.
+//
+// This is useful to abort pipelines feeding Pipe or Multicast when, for
+// instance, an HTTP request is cancelled.
+func WithContext[T any](ctx context.Context, inner ReadStream[T]) ReadStream[T] {
+	if s, ok := inner.(contextAware); ok {
+		s.setContext(ctx)
+	}
+
+	return &ContextStream[T]{
+		ctx:   ctx,
+		inner: inner,
+	}
+}
+
+func (s *ContextStream[T]) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		s.err = err
+		return false
+	}
+
+	if !s.inner.Next() {
+		s.err = s.ctx.Err()
+		return false
+	}
+
+	return true
+}
+
+func (s *ContextStream[T]) Data() T {
+	return s.inner.Data()
+}
+
+func (s *ContextStream[T]) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.inner.Err()
+}
+
+func (s *ContextStream[T]) Close() error {
+	return s.inner.Close()
+}
+
+func (s *ContextStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *ContextStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[any] = new(ContextStream[any])
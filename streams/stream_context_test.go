@@ -0,0 +1,191 @@
+package streams
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ExampleWithContext demonstrates aborting a stream when its context is cancelled.
+func ExampleWithContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Create a stream from a slice of integers bound to the context
+	stream := WithContext(ctx, MemReader([]int{1, 2, 3, 4, 5}, nil))
+
+	for stream.Next() {
+		fmt.Println(stream.Data())
+		if stream.Data() == 2 {
+			cancel()
+		}
+	}
+
+	fmt.Println(stream.Err())
+	// Output:
+	// 1
+	// 2
+	// context canceled
+}
+
+func TestWithContext(t *testing.T) {
+	t.Run("Passes through when context is alive", func(t *testing.T) {
+		stream := WithContext(context.Background(), MemReader([]int{1, 2, 3}, nil))
+
+		result, err := Consume(stream)
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, result)
+	})
+
+	t.Run("Already cancelled context yields nothing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		stream := WithContext(ctx, MemReader([]int{1, 2, 3}, nil))
+
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), context.Canceled)
+		assert.False(t, stream.Next())
+	})
+
+	t.Run("Inner error is reported", func(t *testing.T) {
+		stream := WithContext(
+			context.Background(),
+			Lines(io.NopCloser(&failingReader{err: io.ErrUnexpectedEOF})),
+		)
+
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), io.ErrUnexpectedEOF)
+	})
+
+	t.Run("Interrupts a blocked channel receive", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		ch := make(chan int)
+		stream := WithContext(ctx, Channel(ch))
+
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), context.DeadlineExceeded)
+	})
+
+	t.Run("Keeps the context of the inner channel", func(t *testing.T) {
+		inner, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		stream := WithContext(context.Background(), ChannelContext(inner, make(chan int)))
+
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), context.Canceled)
+		assert.NoError(t, stream.Close())
+	})
+
+	t.Run("Reports the error of the context done first", func(t *testing.T) {
+		outer, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		inner, cancelInner := context.WithCancel(context.Background())
+		defer cancelInner()
+
+		stream := WithContext(outer, ChannelContext(inner, make(chan int)))
+
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), context.DeadlineExceeded)
+	})
+}
+
+func TestChannelContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+
+	stream := ChannelContext(ctx, ch)
+
+	go func() {
+		ch <- 1
+		ch <- 2
+		cancel()
+	}()
+
+	var got []int
+	for stream.Next() {
+		got = append(got, stream.Data())
+	}
+
+	assert.Equal(t, []int{1, 2}, got)
+	assert.ErrorIs(t, stream.Err(), context.Canceled)
+}
+
+func TestContextConstructors(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	t.Run("LinesContext", func(t *testing.T) {
+		stream := LinesContext(cancelled, strings.NewReader("a\nb\n"))
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), context.Canceled)
+	})
+
+	t.Run("ReaderContext", func(t *testing.T) {
+		stream := ReaderContext(cancelled, strings.NewReader("a\nb\n"))
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), context.Canceled)
+	})
+
+	t.Run("JSONContext", func(t *testing.T) {
+		stream := JSONContext[map[string]int](
+			cancelled,
+			io.NopCloser(strings.NewReader(`{"a":1}`+"\n")),
+		)
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), context.Canceled)
+	})
+
+	t.Run("CSV with context", func(t *testing.T) {
+		stream, err := CSV[[]string](
+			WithCSVReader(io.NopCloser(strings.NewReader("a,b\nc,d\n"))),
+			WithCSVContext(cancelled),
+		)
+		assert.NoError(t, err)
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), context.Canceled)
+	})
+
+	t.Run("DBContext", func(t *testing.T) {
+		rows := &mockRows{data: [][]any{{1, "Alice"}}}
+		stream := DBContext(cancelled, rows, func(rows DBRows, u *User) error {
+			return rows.Scan(&u.ID, &u.Name)
+		})
+		assert.False(t, stream.Next())
+		assert.ErrorIs(t, stream.Err(), context.Canceled)
+		assert.True(t, rows.closed)
+	})
+}
+
+func TestPipe_ContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+
+	go func() {
+		ch <- 1
+		cancel()
+	}()
+
+	dst := MemWriter[int]()
+	_, err := Pipe(WithContext(ctx, Channel(ch)), dst)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []int{1}, dst.Items())
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
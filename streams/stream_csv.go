@@ -2,29 +2,58 @@ package streams
 
 import (
 	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"iter"
 	"os"
-	"strings"
+	"reflect"
+	"unicode/utf8"
 )
 
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// ErrCSVSeparator is returned by CSV when the separator is not a single
+// character that can delimit RFC 4180 fields.
+var ErrCSVSeparator = errors.New("invalid csv separator")
+
 type csvUnmarshaler interface {
 	UnmarshalCSV([]string) error
 }
 
+// CSVStream reads RFC 4180 CSV records, so quoted fields may contain
+// separators, quotes and newlines. A leading UTF-8 BOM is skipped, and so are
+// blank lines. A quote inside an unquoted field is an error unless
+// WithCSVLazyQuotes is given.
+//
+// Records are decoded as []string, through UnmarshalCSV, or into struct fields
+// by their `csv:"name"` tag. Errors carry the line and column where they
+// occurred, see csv.ParseError.
 type CSVStream[T any] struct {
+	ctx       context.Context
 	reader    io.ReadCloser
-	buf       *bufio.Scanner
-	sep       string
+	buf       *bufio.Reader
+	csv       *csv.Reader
+	started   bool
+	skipped   int64
+	header    bool
+	columns   []string
+	plan      *csvStruct
+	fields    []int
 	err       error
 	curr      T
 	parseFunc func([]string) (T, error)
 }
 
 // CSV creates a new CSVStream that reads from a given reader or file path.
+// It fails with ErrCSVSeparator if the separator is not a single character
+// other than a quote, a carriage return or a newline.
 func CSV[T any](opts ...CSVOpt) (*CSVStream[T], error) {
 	optsDef := &csvOpts{
+		ctx:    context.Background(),
 		flag:   os.O_RDONLY,
 		perm:   0644,
 		sep:    CSVSeparatorCommaStr,
@@ -36,28 +65,61 @@ func CSV[T any](opts ...CSVOpt) (*CSVStream[T], error) {
 		opt.apply(optsDef)
 	}
 
+	if err := validateCSVSeparator(optsDef.sep); err != nil {
+		return nil, err
+	}
+
+	reader := optsDef.reader
+
 	if optsDef.path != "" {
 		file, err := os.OpenFile(optsDef.path, optsDef.flag, optsDef.perm)
 		if err != nil {
 			return nil, err
 		}
-		return newStreamCSV[T](file, optsDef.sep), nil
+		reader = file
 	}
 
-	return newStreamCSV[T](optsDef.reader, optsDef.sep), nil
+	stream := newStreamCSV[T](reader, optsDef.sep)
+	stream.ctx = optsDef.ctx
+	stream.header = optsDef.header
+	stream.csv.LazyQuotes = optsDef.lazyQuotes
+
+	return stream, nil
+}
+
+func validateCSVSeparator(sep string) error {
+	comma, size := utf8.DecodeRuneInString(sep)
+	if size == 0 || size != len(sep) {
+		return fmt.Errorf("%w: %q must be a single character", ErrCSVSeparator, sep)
+	}
+
+	switch comma {
+	case utf8.RuneError, '"', '\r', '\n':
+		return fmt.Errorf("%w: %q", ErrCSVSeparator, sep)
+	}
+
+	return nil
 }
 
 func newStreamCSV[T any](r io.ReadCloser, sep string) *CSVStream[T] {
 	var zero T
+
+	buf := bufio.NewReader(r)
+	reader := csv.NewReader(buf)
+	reader.FieldsPerRecord = -1
+	if comma, _ := utf8.DecodeRuneInString(sep); comma != utf8.RuneError {
+		reader.Comma = comma
+	}
+
 	stream := &CSVStream[T]{
-		sep:    sep,
+		ctx:    context.Background(),
 		reader: r,
-		buf:    bufio.NewScanner(r),
+		buf:    buf,
+		csv:    reader,
 		curr:   zero,
 	}
 
 	// Determine the parsing strategy based on type T
-	var _ []string = nil
 	var nilSlice T
 
 	// Check if T is []string
@@ -73,16 +135,21 @@ func newStreamCSV[T any](r io.ReadCloser, sep string) *CSVStream[T] {
 				var value T
 				if valuePtr, ok := any(&value).(csvUnmarshaler); ok {
 					if err := valuePtr.UnmarshalCSV(data); err != nil {
-						return zero, err
+						line, _ := stream.csv.FieldPos(0)
+						return zero, fmt.Errorf("record on line %d: %w", line, err)
 					}
 					return value, nil
 				}
 				return zero, fmt.Errorf("type does not implement csvUnmarshaler")
 			}
+		} else if csvIsStruct[T]() {
+			stream.parseFunc = stream.parseStruct
 		} else {
-			// If we get here, T is neither []string nor implements csvUnmarshaler
+			// If we get here, T is neither []string, a struct nor implements csvUnmarshaler
 			stream.parseFunc = func(data []string) (T, error) {
-				return zero, fmt.Errorf("type must be []string or implement csvUnmarshaler")
+				return zero, fmt.Errorf(
+					"type must be []string, a struct or implement csvUnmarshaler",
+				)
 			}
 		}
 	}
@@ -90,24 +157,129 @@ func newStreamCSV[T any](r io.ReadCloser, sep string) *CSVStream[T] {
 	return stream
 }
 
+// parseStruct decodes a record into the struct fields mapped to each column.
+func (s *CSVStream[T]) parseStruct(data []string) (T, error) {
+	var value T
+
+	if s.plan == nil {
+		s.plan = csvStructOf(reflect.TypeFor[T]())
+		s.fields = s.mapColumns(s.plan)
+	}
+
+	plan := s.plan
+
+	v := reflect.ValueOf(&value).Elem()
+
+	for col, cell := range data {
+		if cell == "" || col >= len(s.fields) || s.fields[col] < 0 {
+			continue
+		}
+
+		field := plan.fields[s.fields[col]]
+		target, _ := csvFieldByIndex(v, field.index, true)
+		if err := setCSVField(target, cell, field.layout); err != nil {
+			line, column := s.csv.FieldPos(col)
+			return value, &csv.ParseError{
+				StartLine: line,
+				Line:      line,
+				Column:    column,
+				Err:       fmt.Errorf("field %s: %w", field.name, err),
+			}
+		}
+	}
+
+	return value, nil
+}
+
+// mapColumns resolves the struct field of every column, by header name when a
+// header was read, or by field declaration order otherwise.
+func (s *CSVStream[T]) mapColumns(plan *csvStruct) []int {
+	if s.columns == nil {
+		fields := make([]int, len(plan.fields))
+		for i := range fields {
+			fields[i] = i
+		}
+		return fields
+	}
+
+	fields := make([]int, len(s.columns))
+	for i, name := range s.columns {
+		if idx, ok := plan.byName[name]; ok {
+			fields[i] = idx
+		} else {
+			fields[i] = -1
+		}
+	}
+
+	return fields
+}
+
 func (s *CSVStream[T]) Next() bool {
-	if s.buf.Scan() {
-		data := strings.Split(s.buf.Text(), s.sep)
-		value, err := s.parseFunc(data)
+	if s.err != nil {
+		return false
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		s.err = err
+		return false
+	}
+
+	if !s.started {
+		s.started = true
+
+		// Skip the BOM prepended by Excel and other Windows tools
+		if prefix, err := s.buf.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+			n, _ := s.buf.Discard(len(utf8BOM))
+			s.skipped = int64(n)
+		}
+	}
+
+	if s.header && s.columns == nil {
+		columns, err := s.csv.Read()
 		if err != nil {
-			s.err = err
+			if !errors.Is(err, io.EOF) {
+				s.err = err
+			}
 			return false
 		}
-		s.curr = value
-		return true
+		s.columns = columns
+	}
+
+	data, err := s.csv.Read()
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			s.err = err
+		}
+		return false
 	}
-	return false
+
+	value, err := s.parseFunc(data)
+	if err != nil {
+		s.err = err
+		return false
+	}
+
+	s.curr = value
+	return true
 }
 
 func (s *CSVStream[T]) Data() T {
 	return s.curr
 }
 
+// Header returns the header row, if WithCSVHeader was given and it has
+// already been read.
+func (s *CSVStream[T]) Header() []string {
+	return s.columns
+}
+
+// Offset returns the number of bytes consumed from the reader, up to the end
+// of the current record. A stream resumed from an offset does not see the
+// header row again, so it must not be created with WithCSVHeader.
+func (s *CSVStream[T]) Offset() int64 {
+	return s.skipped + s.csv.InputOffset()
+}
+
 func (s *CSVStream[T]) Err() error {
 	return s.err
 }
@@ -1,6 +1,7 @@
 package streams
 
 import (
+	"context"
 	"io"
 	"os"
 )
@@ -8,11 +9,15 @@ import (
 type CSVOpt func(*csvOpts)
 
 type csvOpts struct {
+	ctx    context.Context
 	path   string
 	reader io.ReadCloser
 	flag   int
 	perm   os.FileMode
 	sep    string
+
+	header     bool
+	lazyQuotes bool
 }
 
 func (fn CSVOpt) apply(c *csvOpts) {
@@ -39,8 +44,35 @@ func WithCSVFilePerm(perm os.FileMode) CSVOpt {
 		o.perm = perm
 	}
 }
+
+// WithCSVSeparator sets the field separator, which must be a single
+// character. CSV fails with ErrCSVSeparator otherwise.
 func WithCSVSeparator(sep string) CSVOpt {
 	return func(o *csvOpts) {
 		o.sep = sep
 	}
 }
+
+// WithCSVContext stops the stream once ctx is done, reporting ctx.Err()
+// through Err.
+func WithCSVContext(ctx context.Context) CSVOpt {
+	return func(o *csvOpts) {
+		o.ctx = ctx
+	}
+}
+
+// WithCSVHeader consumes the first record as a header. When decoding into a
+// struct, columns are matched to fields by the header names.
+func WithCSVHeader() CSVOpt {
+	return func(o *csvOpts) {
+		o.header = true
+	}
+}
+
+// WithCSVLazyQuotes tolerates quotes appearing in unquoted fields and
+// non-doubled quotes in quoted fields, as produced by some exporters.
+func WithCSVLazyQuotes() CSVOpt {
+	return func(o *csvOpts) {
+		o.lazyQuotes = true
+	}
+}
@@ -1,12 +1,16 @@
 package streams
 
 import (
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type csvTest struct {
@@ -88,6 +92,146 @@ a,b,c
 	}
 }
 
+func TestCSVStreamQuotedFields(t *testing.T) {
+	buf := io.NopCloser(strings.NewReader(
+		"\xEF\xBB\xBF" + `id,comment
+1,"hello, world"
+2,"multi
+line"
+3,"say ""hi"""
+`))
+
+	s := newStreamCSV[[]string](buf, ",")
+
+	result, err := Consume(s)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"id", "comment"},
+		{"1", "hello, world"},
+		{"2", "multi\nline"},
+		{"3", `say "hi"`},
+	}, result)
+}
+
+type csvTagged struct {
+	ID      int       `csv:"id"`
+	Name    string    `csv:"name"`
+	Score   float64   `csv:"score"`
+	Active  bool      `csv:"active"`
+	Created time.Time `csv:"created"`
+	Note    *string   `csv:"note"`
+	Ignored string    `csv:"-"`
+}
+
+func TestCSVStreamStructTags(t *testing.T) {
+	buf := io.NopCloser(strings.NewReader(`name;id;unknown;score;active;created;note
+"Doe; John";1;x;9.5;true;2025-12-08T12:00:00Z;
+Alice;2;y;7;false;2025-12-09T08:30:00Z;vip
+`))
+
+	s, err := CSV[csvTagged](
+		WithCSVReader(buf),
+		WithCSVSeparator(";"),
+		WithCSVHeader(),
+	)
+	require.NoError(t, err)
+
+	result, err := Consume(s)
+	require.NoError(t, err)
+
+	vip := "vip"
+	assert.Equal(t, []csvTagged{
+		{
+			ID:      1,
+			Name:    "Doe; John",
+			Score:   9.5,
+			Active:  true,
+			Created: time.Date(2025, 12, 8, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:      2,
+			Name:    "Alice",
+			Score:   7,
+			Created: time.Date(2025, 12, 9, 8, 30, 0, 0, time.UTC),
+			Note:    &vip,
+		},
+	}, result)
+	assert.Equal(t, []string{"name", "id", "unknown", "score", "active", "created", "note"}, s.Header())
+}
+
+func TestCSVStreamStructWithoutHeader(t *testing.T) {
+	type row struct {
+		A string `csv:"a"`
+		B int    `csv:"b"`
+	}
+
+	s := newStreamCSV[row](io.NopCloser(strings.NewReader("x,1\ny,2\n")), ",")
+
+	result, err := Consume(s)
+	require.NoError(t, err)
+	assert.Equal(t, []row{{"x", 1}, {"y", 2}}, result)
+}
+
+func TestCSVStreamErrorPositions(t *testing.T) {
+	t.Run("Field conversion error", func(t *testing.T) {
+		buf := io.NopCloser(strings.NewReader("id,name\n1,Alice\n2,Bob\nthree,Carol\n"))
+
+		s, err := CSV[csvTagged](WithCSVReader(buf), WithCSVHeader())
+		require.NoError(t, err)
+
+		result, err := Consume(s)
+		assert.Nil(t, result)
+
+		var parseErr *csv.ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, 4, parseErr.Line)
+		assert.Equal(t, 1, parseErr.Column)
+		assert.Contains(t, err.Error(), "field id")
+	})
+
+	t.Run("Malformed quotes", func(t *testing.T) {
+		buf := io.NopCloser(strings.NewReader("a,b\nc,\"d\"e\"\n"))
+
+		s := newStreamCSV[[]string](buf, ",")
+
+		_, err := Consume(s)
+
+		var parseErr *csv.ParseError
+		require.True(t, errors.As(err, &parseErr))
+		assert.Equal(t, 2, parseErr.Line)
+		assert.Equal(t, 5, parseErr.Column)
+	})
+
+	t.Run("UnmarshalCSV error", func(t *testing.T) {
+		buf := io.NopCloser(strings.NewReader("a,b\nc\n"))
+
+		s := newStreamCSV[csvTest](buf, ",")
+
+		_, err := Consume(s)
+		assert.EqualError(t, err, "record on line 2: want at least 2 cols, have 1")
+	})
+}
+
+func TestCSVStreamSeparator(t *testing.T) {
+	s, err := CSV[[]string](
+		WithCSVReader(io.NopCloser(strings.NewReader("a;b\n\nc;d\n"))),
+		WithCSVSeparator(";"),
+	)
+	require.NoError(t, err)
+
+	result, err := Consume(s)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a", "b"}, {"c", "d"}}, result, "blank lines are skipped")
+
+	for _, sep := range []string{"", "::", "\"", "\n", "\r", "\xff"} {
+		_, err := CSV[[]string](
+			WithCSVReader(io.NopCloser(strings.NewReader("a"))),
+			WithCSVSeparator(sep),
+		)
+		assert.ErrorIs(t, err, ErrCSVSeparator, "separator %q", sep)
+	}
+}
+
 // ExampleCSV demonstrates reading CSV data from a string.
 func ExampleCSV() {
 	// Create a CSV reader from a string
@@ -111,3 +255,27 @@ func ExampleCSV() {
 	// Row 3: [Bob 30 LA]
 	// Row 4: [Charlie 35 Chicago]
 }
+
+// ExampleCSV_structTags demonstrates decoding CSV records into structs by header name.
+func ExampleCSV_structTags() {
+	type person struct {
+		Name string `csv:"name"`
+		Age  int    `csv:"age"`
+	}
+
+	csvData := "age,name\n25,\"Smith, Alice\"\n30,Bob"
+	reader := io.NopCloser(strings.NewReader(csvData))
+
+	csvStream, _ := CSV[person](
+		WithCSVReader(reader),
+		WithCSVHeader(),
+	)
+
+	result, _ := Consume(csvStream)
+	for _, p := range result {
+		fmt.Printf("%s is %d\n", p.Name, p.Age)
+	}
+	// Output:
+	// Smith, Alice is 25
+	// Bob is 30
+}
@@ -1,5 +1,7 @@
 package streams
 
+import "context"
+
 // DBRows is an interface that abstracts database rows operations.
 // It's compatible with sql.Rows and pgx.Rows among others.
 type DBRows interface {
@@ -13,6 +15,7 @@ type DBRows interface {
 //
 // The DBStream will automatically close the underlying rows when Next() returns false.
 type DBStream[T any] struct {
+	ctx     context.Context
 	rows    DBRows
 	scanFn  func(DBRows, *T) error
 	current *T
@@ -25,6 +28,12 @@ var _ ReadStream[any] = new(DBStream[any])
 // It returns true if there was a next row, false if there are no more rows or an error occurred.
 // The underlying rows are automatically closed when Next returns false.
 func (s *DBStream[T]) Next() bool {
+	if err := s.ctx.Err(); err != nil {
+		s.err = err
+		_ = s.rows.Close()
+		return false
+	}
+
 	keep := s.rows.Next()
 
 	if keep {
@@ -68,7 +77,18 @@ func (s *DBStream[T]) Close() error {
 //		return rows.Scan(&user.ID, &user.Name)
 //	})
 func DB[T any](rows DBRows, scanFn func(DBRows, *T) error) *DBStream[T] {
+	return DBContext(context.Background(), rows, scanFn)
+}
+
+// DBContext is like DB but stops iterating once ctx is done, closing the
+// underlying rows and reporting ctx.Err() through Err.
+func DBContext[T any](
+	ctx context.Context,
+	rows DBRows,
+	scanFn func(DBRows, *T) error,
+) *DBStream[T] {
 	return &DBStream[T]{
+		ctx:    ctx,
 		rows:   rows,
 		scanFn: scanFn,
 	}
@@ -0,0 +1,226 @@
+package streams
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+type (
+	// DBColumnRows is a DBRows that also reports the names of its columns,
+	// such as sql.Rows and db.Rows.
+	DBColumnRows interface {
+		DBRows
+		Columns() ([]string, error)
+	}
+
+	// dbField describes the struct field a column is scanned into.
+	dbField struct {
+		name  string
+		index []int
+		// option is the value type of an fp.Option field, nil otherwise.
+		option reflect.Type
+	}
+
+	// dbStruct is the cached field plan of a struct type.
+	dbStruct struct {
+		fields []dbField
+		byName map[string]int
+	}
+
+	// dbStructScanner scans rows into the fields of T matching each column,
+	// resolved once from the columns of the first row.
+	dbStructScanner[T any] struct {
+		rows    DBColumnRows
+		plan    *dbStruct
+		fields  []int
+		targets []any
+	}
+)
+
+var dbStructCache sync.Map // map[reflect.Type]*dbStruct
+
+// DBStruct creates a new DBStream that scans every row into a struct T,
+// matching columns to fields by their `db:"col"` tag, or by field name,
+// ignoring case, when untagged. Fields tagged with `db:"-"` are skipped, as
+// are columns without a field. Untagged embedded structs are flattened.
+//
+// Fields are scanned as database/sql would, so any sql.Scanner such as
+// num.Dec, db.NullJSON or db.NullJSONArray works, as do pointers for
+// nullable columns. fp.Option fields are None for NULL columns.
+//
+// An error is returned if T is not a struct, in which case rows are left for
+// the caller to close.
+func DBStruct[T any](rows DBColumnRows) (*DBStream[T], error) {
+	return DBStructContext[T](context.Background(), rows)
+}
+
+// DBStructContext is like DBStruct but stops iterating once ctx is done,
+// closing the underlying rows and reporting ctx.Err() through Err.
+func DBStructContext[T any](ctx context.Context, rows DBColumnRows) (*DBStream[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("db struct: type must be a struct, got %s", t)
+	}
+
+	scanner := &dbStructScanner[T]{
+		rows: rows,
+		plan: dbStructOf(t),
+	}
+
+	return DBContext(ctx, rows, scanner.scan), nil
+}
+
+func (s *dbStructScanner[T]) scan(_ DBRows, dst *T) error {
+	if s.fields == nil {
+		columns, err := s.rows.Columns()
+		if err != nil {
+			return err
+		}
+
+		s.fields = s.plan.mapColumns(columns)
+		s.targets = make([]any, len(columns))
+	}
+
+	v := reflect.ValueOf(dst).Elem()
+
+	for col, idx := range s.fields {
+		if idx < 0 {
+			s.targets[col] = new(any)
+			continue
+		}
+
+		field := s.plan.fields[idx]
+		target, _ := csvFieldByIndex(v, field.index, true)
+
+		if field.option != nil {
+			// Scanned into a pointer so that NULL leaves the option as None
+			s.targets[col] = reflect.New(reflect.PointerTo(field.option)).Interface()
+			continue
+		}
+
+		s.targets[col] = target.Addr().Interface()
+	}
+
+	if err := s.rows.Scan(s.targets...); err != nil {
+		return err
+	}
+
+	for col, idx := range s.fields {
+		if idx < 0 || s.plan.fields[idx].option == nil {
+			continue
+		}
+
+		field := s.plan.fields[idx]
+		target, _ := csvFieldByIndex(v, field.index, true)
+
+		if err := setDBOption(target, s.targets[col]); err != nil {
+			return fmt.Errorf("field %s: %w", field.name, err)
+		}
+	}
+
+	return nil
+}
+
+// setDBOption sets an fp.Option field to Some of the value scanned into ptr,
+// a pointer to a pointer of its value type, unless it is nil. As with CSV,
+// the option is built through its JSON unmarshaler.
+func setDBOption(v reflect.Value, ptr any) error {
+	elem := reflect.ValueOf(ptr).Elem()
+	if elem.IsNil() {
+		v.SetZero()
+		return nil
+	}
+
+	data, err := json.Marshal(elem.Interface())
+	if err != nil {
+		return err
+	}
+
+	return v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON(data)
+}
+
+// dbStructOf returns the field plan of struct type t, building and caching
+// it on first use.
+func dbStructOf(t reflect.Type) *dbStruct {
+	if cached, ok := dbStructCache.Load(t); ok {
+		return cached.(*dbStruct)
+	}
+
+	plan := &dbStruct{byName: make(map[string]int)}
+	plan.collect(t, nil)
+
+	for i, f := range plan.fields {
+		if _, ok := plan.byName[f.name]; !ok {
+			plan.byName[f.name] = i
+		}
+	}
+
+	cached, _ := dbStructCache.LoadOrStore(t, plan)
+	return cached.(*dbStruct)
+}
+
+func (p *dbStruct) collect(t reflect.Type, index []int) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag, _, _ := strings.Cut(f.Tag.Get("db"), ",")
+		if tag == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+
+		if f.Anonymous && tag == "" && csvIsNested(f.Type) {
+			p.collect(csvDeref(f.Type), fieldIndex)
+			continue
+		}
+
+		if !f.IsExported() {
+			continue
+		}
+
+		name := tag
+		if name == "" {
+			name = f.Name
+		}
+
+		field := dbField{name: name, index: fieldIndex}
+
+		if f.Type.Implements(optionalType) {
+			unwrap, ok := f.Type.MethodByName("Unwrap")
+			if ok && unwrap.Type.NumOut() == 2 {
+				field.option = unwrap.Type.Out(0)
+			}
+		}
+
+		p.fields = append(p.fields, field)
+	}
+}
+
+// mapColumns resolves the field of every column, by exact name first and
+// then ignoring case, or -1 when no field matches.
+func (p *dbStruct) mapColumns(columns []string) []int {
+	fields := make([]int, len(columns))
+
+	for i, column := range columns {
+		fields[i] = -1
+
+		if idx, ok := p.byName[column]; ok {
+			fields[i] = idx
+			continue
+		}
+
+		for idx, f := range p.fields {
+			if strings.EqualFold(f.name, column) {
+				fields[i] = idx
+				break
+			}
+		}
+	}
+
+	return fields
+}
@@ -0,0 +1,205 @@
+package streams
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sonirico/vago/fp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticConnector serves the same result set to every query, so that
+// DBStruct can be tested against real sql.Rows.
+type staticConnector struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+type staticConn struct{ c *staticConnector }
+
+type staticStmt struct{ c *staticConnector }
+
+type staticRows struct {
+	c   *staticConnector
+	pos int
+}
+
+func (c *staticConnector) Connect(context.Context) (driver.Conn, error) { return staticConn{c}, nil }
+func (c *staticConnector) Driver() driver.Driver                        { return nil }
+
+func (c staticConn) Prepare(string) (driver.Stmt, error) { return staticStmt(c), nil }
+func (staticConn) Close() error                          { return nil }
+func (staticConn) Begin() (driver.Tx, error)             { return nil, errors.New("not supported") }
+
+func (staticStmt) Close() error  { return nil }
+func (staticStmt) NumInput() int { return -1 }
+func (staticStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (s staticStmt) Query([]driver.Value) (driver.Rows, error) { return &staticRows{c: s.c}, nil }
+
+func (r *staticRows) Columns() []string { return r.c.columns }
+func (r *staticRows) Close() error      { return nil }
+func (r *staticRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.c.rows) {
+		return io.EOF
+	}
+	copy(dest, r.c.rows[r.pos])
+	r.pos++
+	return nil
+}
+
+func queryStatic(t *testing.T, columns []string, rows ...[]driver.Value) *sql.Rows {
+	t.Helper()
+
+	conn := sql.OpenDB(&staticConnector{columns: columns, rows: rows})
+	t.Cleanup(func() { _ = conn.Close() })
+
+	result, err := conn.Query("SELECT")
+	require.NoError(t, err)
+	return result
+}
+
+// jsonTags mimics db.NullJSONArray, a sql.Scanner decoding a JSON column.
+type jsonTags struct {
+	Tags  []string
+	Valid bool
+}
+
+func (j *jsonTags) Scan(value any) error {
+	if value == nil {
+		return nil
+	}
+	j.Valid = true
+	return json.Unmarshal(value.([]byte), &j.Tags)
+}
+
+type dbAudit struct {
+	CreatedAt time.Time `db:"created_at"`
+}
+
+type dbAccount struct {
+	dbAudit
+	ID       int64              `db:"id"`
+	Name     string             `db:"name"`
+	Email    *string            `db:"email"`
+	Balance  fp.Option[float64] `db:"balance"`
+	Tags     jsonTags           `db:"tags"`
+	Internal string             `db:"-"`
+	Country  string
+}
+
+func TestDBStruct(t *testing.T) {
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	email := "ada@example.com"
+
+	t.Run("Maps columns to fields", func(t *testing.T) {
+		rows := queryStatic(t,
+			[]string{"id", "name", "email", "balance", "tags", "created_at", "COUNTRY", "unknown", "Internal"},
+			[]driver.Value{int64(1), "ada", email, 10.5, []byte(`["vip"]`), created, "ES", "x", "secret"},
+			[]driver.Value{int64(2), "bob", nil, nil, nil, created, "FR", "y", "secret"},
+		)
+
+		s, err := DBStruct[dbAccount](rows)
+		require.NoError(t, err)
+
+		result, err := Consume(s)
+		require.NoError(t, err)
+
+		assert.Equal(t, []dbAccount{
+			{
+				dbAudit: dbAudit{CreatedAt: created},
+				ID:      1,
+				Name:    "ada",
+				Email:   &email,
+				Balance: fp.Some(10.5),
+				Tags:    jsonTags{Tags: []string{"vip"}, Valid: true},
+				Country: "ES",
+			},
+			{
+				dbAudit: dbAudit{CreatedAt: created},
+				ID:      2,
+				Name:    "bob",
+				Balance: fp.None[float64](),
+				Country: "FR",
+			},
+		}, result)
+	})
+
+	t.Run("Scan errors", func(t *testing.T) {
+		rows := queryStatic(t, []string{"id"}, []driver.Value{"not a number"})
+
+		s, err := DBStruct[dbAccount](rows)
+		require.NoError(t, err)
+		assert.True(t, s.Next())
+		assert.ErrorContains(t, s.Err(), "converting driver.Value type string")
+	})
+
+	t.Run("Context stops the stream", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		rows := queryStatic(t, []string{"id"}, []driver.Value{int64(1)})
+
+		s, err := DBStructContext[dbAccount](ctx, rows)
+		require.NoError(t, err)
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), context.Canceled)
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		rows := queryStatic(t, []string{"id"}, []driver.Value{int64(1)})
+
+		_, err := DBStruct[int64](rows)
+		assert.ErrorContains(t, err, "type must be a struct, got int64")
+
+		_, err = DBStruct[*dbAccount](rows)
+		assert.ErrorContains(t, err, "type must be a struct, got *streams.dbAccount")
+	})
+}
+
+// ExampleDBStruct demonstrates streaming query results into structs without a scan function.
+func ExampleDBStruct() {
+	type user struct {
+		ID    int64              `db:"id"`
+		Name  string             `db:"name"`
+		Score fp.Option[float64] `db:"score"`
+	}
+
+	conn := sql.OpenDB(&staticConnector{
+		columns: []string{"id", "name", "score"},
+		rows: [][]driver.Value{
+			{int64(1), "ada", 9.5},
+			{int64(2), "bob", nil},
+		},
+	})
+	defer conn.Close()
+
+	rows, err := conn.Query("SELECT id, name, score FROM users")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	users, err := DBStruct[user](rows)
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+
+	for users.Next() {
+		u := users.Data()
+		fmt.Println(u.ID, u.Name, u.Score.UnwrapOr(0))
+	}
+	// Output:
+	// 1 ada 9.5
+	// 2 bob 0
+}
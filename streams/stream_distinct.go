@@ -0,0 +1,187 @@
+package streams
+
+import (
+	"container/list"
+	"iter"
+	"time"
+
+	"github.com/sonirico/vago/clock"
+)
+
+type (
+	distinctEntry[K comparable] struct {
+		key  K
+		seen time.Time
+	}
+
+	// DistinctStream yields the items of the inner stream whose key has not
+	// been seen before.
+	//
+	// Seen keys are kept in a list ordered by the last time they were seen,
+	// so both the LRU and the TTL bounds evict from its back.
+	DistinctStream[T any, K comparable] struct {
+		inner   ReadStream[T]
+		keyFn   func(T) K
+		opts    distinctOpts
+		seen    map[K]*list.Element
+		recent  *list.List
+		current T
+	}
+
+	// DedupConsecutiveStream drops items whose key equals the key of the
+	// previous item.
+	DedupConsecutiveStream[T any, K comparable] struct {
+		inner   ReadStream[T]
+		keyFn   func(T) K
+		last    K
+		current T
+		started bool
+	}
+)
+
+// Distinct creates a new ReadStream that drops every item whose key, as
+// returned by keyFn, was already seen. The first item with each key is kept.
+//
+// By default every key is remembered until the stream ends. Use
+// WithDistinctLRU or WithDistinctTTL to bound the memory on long-running
+// streams, at the cost of letting through duplicates that were forgotten.
+func Distinct[T any, K comparable](
+	inner ReadStream[T],
+	keyFn func(T) K,
+	opts ...DistinctOpt,
+) ReadStream[T] {
+	optsDef := distinctOpts{
+		clock: clock.New(),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	return &DistinctStream[T, K]{
+		inner:  inner,
+		keyFn:  keyFn,
+		opts:   optsDef,
+		seen:   make(map[K]*list.Element),
+		recent: list.New(),
+	}
+}
+
+func (s *DistinctStream[T, K]) Next() bool {
+	for s.inner.Next() {
+		data := s.inner.Data()
+		key := s.keyFn(data)
+
+		var now time.Time
+		if s.opts.ttl > 0 {
+			now = s.opts.clock.Now()
+			s.expire(now)
+		}
+
+		if elem, ok := s.seen[key]; ok {
+			elem.Value.(*distinctEntry[K]).seen = now
+			s.recent.MoveToFront(elem)
+			continue
+		}
+
+		s.seen[key] = s.recent.PushFront(&distinctEntry[K]{key: key, seen: now})
+
+		if s.opts.size > 0 && s.recent.Len() > s.opts.size {
+			s.evict(s.recent.Back())
+		}
+
+		s.current = data
+		return true
+	}
+
+	return false
+}
+
+// expire forgets the keys last seen more than ttl ago.
+func (s *DistinctStream[T, K]) expire(now time.Time) {
+	for elem := s.recent.Back(); elem != nil; elem = s.recent.Back() {
+		if now.Sub(elem.Value.(*distinctEntry[K]).seen) < s.opts.ttl {
+			return
+		}
+		s.evict(elem)
+	}
+}
+
+func (s *DistinctStream[T, K]) evict(elem *list.Element) {
+	entry := s.recent.Remove(elem).(*distinctEntry[K])
+	delete(s.seen, entry.key)
+}
+
+func (s *DistinctStream[T, K]) Data() T {
+	return s.current
+}
+
+func (s *DistinctStream[T, K]) Err() error {
+	return s.inner.Err()
+}
+
+func (s *DistinctStream[T, K]) Close() error {
+	return s.inner.Close()
+}
+
+func (s *DistinctStream[T, K]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *DistinctStream[T, K]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+// DedupConsecutive creates a new ReadStream that drops items whose key, as
+// returned by keyFn, equals the key of the item right before them. Unlike
+// Distinct it only remembers the last key, so it suits streams already
+// sorted or grouped by key.
+func DedupConsecutive[T any, K comparable](inner ReadStream[T], keyFn func(T) K) ReadStream[T] {
+	return &DedupConsecutiveStream[T, K]{
+		inner: inner,
+		keyFn: keyFn,
+	}
+}
+
+func (s *DedupConsecutiveStream[T, K]) Next() bool {
+	for s.inner.Next() {
+		data := s.inner.Data()
+		key := s.keyFn(data)
+
+		if s.started && key == s.last {
+			continue
+		}
+
+		s.started = true
+		s.last = key
+		s.current = data
+		return true
+	}
+
+	return false
+}
+
+func (s *DedupConsecutiveStream[T, K]) Data() T {
+	return s.current
+}
+
+func (s *DedupConsecutiveStream[T, K]) Err() error {
+	return s.inner.Err()
+}
+
+func (s *DedupConsecutiveStream[T, K]) Close() error {
+	return s.inner.Close()
+}
+
+func (s *DedupConsecutiveStream[T, K]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *DedupConsecutiveStream[T, K]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+var (
+	_ ReadStream[any] = new(DistinctStream[any, int])
+	_ ReadStream[any] = new(DedupConsecutiveStream[any, int])
+)
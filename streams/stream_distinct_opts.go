@@ -0,0 +1,42 @@
+package streams
+
+import (
+	"time"
+
+	"github.com/sonirico/vago/clock"
+)
+
+type DistinctOpt func(*distinctOpts)
+
+type distinctOpts struct {
+	clock clock.Clock
+	size  int
+	ttl   time.Duration
+}
+
+func (fn DistinctOpt) apply(o *distinctOpts) {
+	fn(o)
+}
+
+// WithDistinctLRU bounds the memory of Distinct to the size most recently
+// seen keys. A key evicted from memory is yielded again when it reappears.
+func WithDistinctLRU(size int) DistinctOpt {
+	return func(o *distinctOpts) {
+		o.size = size
+	}
+}
+
+// WithDistinctTTL makes Distinct forget a key once ttl has elapsed since it
+// was last seen, so it is only de-duplicated within that time window.
+func WithDistinctTTL(ttl time.Duration) DistinctOpt {
+	return func(o *distinctOpts) {
+		o.ttl = ttl
+	}
+}
+
+// WithDistinctClock sets the clock used to expire keys with WithDistinctTTL.
+func WithDistinctClock(c clock.Clock) DistinctOpt {
+	return func(o *distinctOpts) {
+		o.clock = c
+	}
+}
@@ -0,0 +1,161 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sonirico/vago/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+type distinctEvent struct {
+	ID   string
+	Seq  int
+	Wait time.Duration
+}
+
+func TestDistinctStream(t *testing.T) {
+	errRead := errors.New("read failed")
+
+	tests := []struct {
+		name        string
+		input       []distinctEvent
+		err         error
+		opts        []DistinctOpt
+		expected    []int
+		expectedErr error
+	}{
+		{
+			name:     "Empty",
+			input:    []distinctEvent{},
+			expected: nil,
+		},
+		{
+			name: "Unbounded memory",
+			input: []distinctEvent{
+				{ID: "a", Seq: 1}, {ID: "b", Seq: 2}, {ID: "a", Seq: 3},
+				{ID: "c", Seq: 4}, {ID: "b", Seq: 5}, {ID: "a", Seq: 6},
+			},
+			expected: []int{1, 2, 4},
+		},
+		{
+			name: "LRU forgets the least recently seen key",
+			input: []distinctEvent{
+				{ID: "a", Seq: 1}, {ID: "b", Seq: 2}, {ID: "a", Seq: 3},
+				{ID: "c", Seq: 4}, {ID: "b", Seq: 5}, {ID: "a", Seq: 6},
+			},
+			opts:     []DistinctOpt{WithDistinctLRU(2)},
+			expected: []int{1, 2, 4, 5, 6},
+		},
+		{
+			name: "TTL forgets expired keys",
+			input: []distinctEvent{
+				{ID: "a", Seq: 1},
+				{ID: "a", Seq: 2, Wait: 5 * time.Second},
+				{ID: "b", Seq: 3, Wait: 5 * time.Second},
+				{ID: "a", Seq: 4, Wait: 9 * time.Second},
+				{ID: "b", Seq: 5},
+			},
+			opts:     []DistinctOpt{WithDistinctTTL(10 * time.Second)},
+			expected: []int{1, 3, 4},
+		},
+		{
+			name:        "Error is propagated",
+			input:       []distinctEvent{{ID: "a", Seq: 1}, {ID: "a", Seq: 2}},
+			err:         errRead,
+			expected:    []int{1},
+			expectedErr: errRead,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mock := clock.NewMock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+			// advance the clock as each event is read
+			src := Map(MemReader(tc.input, tc.err), func(e distinctEvent) distinctEvent {
+				mock.Add(e.Wait)
+				return e
+			})
+
+			opts := append([]DistinctOpt{WithDistinctClock(mock)}, tc.opts...)
+			s := Distinct(src, func(e distinctEvent) string { return e.ID }, opts...)
+
+			var got []int
+			for s.Next() {
+				got = append(got, s.Data().Seq)
+			}
+
+			assert.Equal(t, tc.expected, got)
+			assert.ErrorIs(t, s.Err(), tc.expectedErr)
+			assert.NoError(t, s.Close())
+		})
+	}
+}
+
+func TestDedupConsecutiveStream(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []int
+		expected []int
+	}{
+		{
+			name:     "Empty",
+			input:    []int{},
+			expected: nil,
+		},
+		{
+			name:     "Zero value first",
+			input:    []int{0, 0, 1},
+			expected: []int{0, 1},
+		},
+		{
+			name:     "Runs",
+			input:    []int{1, 1, 2, 2, 2, 1, 3, 3},
+			expected: []int{1, 2, 1, 3},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := DedupConsecutive(MemReader(tc.input, nil), func(x int) int { return x })
+
+			var got []int
+			for s.Next() {
+				got = append(got, s.Data())
+			}
+
+			assert.Equal(t, tc.expected, got)
+			assert.NoError(t, s.Err())
+		})
+	}
+}
+
+// ExampleDistinct demonstrates dropping redelivered events by ID.
+func ExampleDistinct() {
+	type event struct {
+		ID     string
+		Action string
+	}
+
+	events := MemReader([]event{
+		{"evt-1", "created"},
+		{"evt-2", "paid"},
+		{"evt-1", "created"},
+		{"evt-3", "shipped"},
+	}, nil)
+
+	stream := Distinct(events, func(e event) string { return e.ID }, WithDistinctLRU(10_000))
+	defer stream.Close()
+
+	for stream.Next() {
+		e := stream.Data()
+		fmt.Println(e.ID, e.Action)
+	}
+	// Output:
+	// evt-1 created
+	// evt-2 paid
+	// evt-3 shipped
+}
@@ -0,0 +1,154 @@
+package streams
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FixedWidthColumn locates a column of a fixed-width record by the position
+// of its first character, starting at zero, and its length in characters.
+type FixedWidthColumn struct {
+	Name  string
+	Start int
+	Len   int
+}
+
+// fixedWidthField is a column decoded into a struct field.
+type fixedWidthField struct {
+	column FixedWidthColumn
+	index  []int
+	layout string
+}
+
+// FixedWidth creates a new ReadStream that parses fixed-width records, such as
+// mainframe bank statements, one per line. Blank lines are skipped and cells
+// are trimmed of the spaces padding them. Lines shorter than the layout yield
+// empty cells.
+//
+// T may be a map[string]string, keyed by column name, or a struct. Struct
+// fields are decoded as with CSV, and located by layout, matching column
+// names to field names ignoring case, or, when layout is nil, by their
+// `fw:"start,len"` tags. Every layout column must match an exported field,
+// and neither its start nor its length may be negative.
+//
+// A line that cannot be decoded stops the stream with a *LineError, unless
+// WithLineRecordSkipErrors is given.
+func FixedWidth[T any](r io.Reader, layout []FixedWidthColumn, opts ...LineRecordOpt) ReadStream[T] {
+	parse, err := fixedWidthParser[T](layout)
+	stream := newLineRecordStream(r, parse, opts)
+	stream.err = err
+	return stream
+}
+
+func fixedWidthParser[T any](layout []FixedWidthColumn) (func(string) (T, error), error) {
+	t := reflect.TypeFor[T]()
+
+	for _, col := range layout {
+		if col.Start < 0 || col.Len < 0 {
+			return nil, fmt.Errorf("fixed width: invalid column %q, start %d and len %d must not be negative",
+				col.Name, col.Start, col.Len)
+		}
+	}
+
+	if t == reflect.TypeFor[map[string]string]() {
+		if len(layout) == 0 {
+			return nil, fmt.Errorf("fixed width: a layout is required for %s", t)
+		}
+
+		return func(line string) (T, error) {
+			runes := []rune(line)
+			record := make(map[string]string, len(layout))
+
+			for _, col := range layout {
+				record[col.Name] = fixedWidthCell(runes, col)
+			}
+
+			return any(record).(T), nil
+		}, nil
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("fixed width: type must be map[string]string or a struct, got %s", t)
+	}
+
+	fields, err := fixedWidthFields(t, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(line string) (T, error) {
+		runes := []rune(line)
+
+		var value T
+		v := reflect.ValueOf(&value).Elem()
+
+		for _, f := range fields {
+			if err := setCSVField(v.FieldByIndex(f.index), fixedWidthCell(runes, f.column), f.layout); err != nil {
+				return value, fmt.Errorf("field %s: %w", f.column.Name, err)
+			}
+		}
+
+		return value, nil
+	}, nil
+}
+
+// fixedWidthFields locates the fields of struct type t, by layout or by their
+// `fw` tags.
+func fixedWidthFields(t reflect.Type, layout []FixedWidthColumn) ([]fixedWidthField, error) {
+	var fields []fixedWidthField
+
+	if layout != nil {
+		for _, col := range layout {
+			f, ok := t.FieldByNameFunc(func(name string) bool {
+				return strings.EqualFold(name, col.Name)
+			})
+			if !ok || !f.IsExported() {
+				return nil, fmt.Errorf("fixed width: column %q matches no exported field of %s", col.Name, t)
+			}
+
+			fields = append(fields, fixedWidthField{column: col, index: f.Index, layout: f.Tag.Get("layout")})
+		}
+
+		return fields, nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag := f.Tag.Get("fw")
+		if tag == "" || tag == "-" || !f.IsExported() {
+			continue
+		}
+
+		start, size, ok := strings.Cut(tag, ",")
+		col := FixedWidthColumn{Name: f.Name}
+
+		var errStart, errLen error
+		col.Start, errStart = strconv.Atoi(strings.TrimSpace(start))
+		col.Len, errLen = strconv.Atoi(strings.TrimSpace(size))
+
+		if !ok || errStart != nil || errLen != nil || col.Start < 0 || col.Len < 0 {
+			return nil, fmt.Errorf("fixed width: invalid tag %q on field %s, want `fw:\"start,len\"`", tag, f.Name)
+		}
+
+		fields = append(fields, fixedWidthField{column: col, index: f.Index, layout: f.Tag.Get("layout")})
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("fixed width: %s has no `fw` tagged fields", t)
+	}
+
+	return fields, nil
+}
+
+func fixedWidthCell(runes []rune, col FixedWidthColumn) string {
+	if col.Start >= len(runes) {
+		return ""
+	}
+
+	end := min(col.Start+col.Len, len(runes))
+	return strings.TrimSpace(string(runes[col.Start:end]))
+}
@@ -0,0 +1,166 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fwMovement struct {
+	Date    time.Time `fw:"0,8"   layout:"20060102"`
+	Concept string    `fw:"8,12"`
+	Amount  float64   `fw:"20,10"`
+	Ignored string
+}
+
+const fwStatement = "20240105Nómina        1500.00\n" +
+	"\n" +
+	"20240107Alquiler     -750.50\r\n" +
+	"20240109Café"
+
+func TestFixedWidth(t *testing.T) {
+	t.Run("Struct tags", func(t *testing.T) {
+		result, err := Consume(FixedWidth[fwMovement](strings.NewReader(fwStatement), nil))
+		require.NoError(t, err)
+
+		assert.Equal(t, []fwMovement{
+			{Date: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC), Concept: "Nómina", Amount: 1500},
+			{Date: time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC), Concept: "Alquiler", Amount: -750.5},
+			{Date: time.Date(2024, 1, 9, 0, 0, 0, 0, time.UTC), Concept: "Café"},
+		}, result)
+	})
+
+	t.Run("Layout into a map", func(t *testing.T) {
+		layout := []FixedWidthColumn{{"date", 0, 8}, {"amount", 20, 10}}
+
+		result, err := Consume(FixedWidth[map[string]string](strings.NewReader(fwStatement), layout))
+		require.NoError(t, err)
+
+		assert.Equal(t, []map[string]string{
+			{"date": "20240105", "amount": "1500.00"},
+			{"date": "20240107", "amount": "-750.50"},
+			{"date": "20240109", "amount": ""},
+		}, result)
+	})
+
+	t.Run("Layout into a struct", func(t *testing.T) {
+		type movement struct {
+			Concept string
+			Amount  float64
+		}
+
+		layout := []FixedWidthColumn{{"concept", 8, 12}, {"amount", 20, 10}}
+
+		result, err := Consume(FixedWidth[movement](strings.NewReader(fwStatement), layout))
+		require.NoError(t, err)
+		assert.Equal(t, []movement{{"Nómina", 1500}, {"Alquiler", -750.5}, {"Café", 0}}, result)
+	})
+
+	t.Run("Malformed line", func(t *testing.T) {
+		input := "20240105Nómina        1500.00\n2024XX07Alquiler     -750.50\n"
+
+		s := FixedWidth[fwMovement](strings.NewReader(input), nil)
+		assert.True(t, s.Next())
+		assert.False(t, s.Next())
+
+		var lineErr *LineError
+		require.ErrorAs(t, s.Err(), &lineErr)
+		assert.Equal(t, 2, lineErr.Line)
+		assert.ErrorContains(t, s.Err(), "line 2: field Date")
+	})
+
+	t.Run("Skip malformed lines", func(t *testing.T) {
+		input := "2024XX05Nómina        1500.00\n\n20240107Alquiler     -750.50\n20240109Café         abc\n"
+
+		var skipped []int
+		s := FixedWidth[fwMovement](strings.NewReader(input), nil,
+			WithLineRecordSkipErrors(func(err *LineError) { skipped = append(skipped, err.Line) }),
+		)
+
+		result, err := Consume(s)
+		require.NoError(t, err)
+		require.Len(t, result, 1)
+		assert.Equal(t, "Alquiler", result[0].Concept)
+		assert.Equal(t, []int{1, 4}, skipped)
+	})
+
+	t.Run("Invalid tags", func(t *testing.T) {
+		type bad struct {
+			Field string `fw:"3"`
+		}
+
+		s := FixedWidth[bad](strings.NewReader("abc"), nil)
+		assert.False(t, s.Next())
+		assert.ErrorContains(t, s.Err(), `invalid tag "3" on field Field`)
+	})
+
+	t.Run("Invalid layouts", func(t *testing.T) {
+		type movement struct {
+			Concept string
+		}
+
+		tests := []struct {
+			name   string
+			layout []FixedWidthColumn
+			errMsg string
+		}{
+			{"Negative start", []FixedWidthColumn{{"concept", -1, 12}}, `invalid column "concept"`},
+			{"Negative length", []FixedWidthColumn{{"concept", 8, -4}}, `invalid column "concept"`},
+			{"Unknown column", []FixedWidthColumn{{"concept", 8, 12}, {"missing", 0, 1}}, `column "missing" matches no exported field`},
+		}
+
+		for _, tc := range tests {
+			t.Run(tc.name, func(t *testing.T) {
+				s := FixedWidth[movement](strings.NewReader(fwStatement), tc.layout)
+				assert.False(t, s.Next())
+				assert.ErrorContains(t, s.Err(), tc.errMsg)
+			})
+		}
+
+		s := FixedWidth[map[string]string](strings.NewReader(fwStatement), []FixedWidthColumn{{"date", -8, 8}})
+		assert.False(t, s.Next())
+		assert.ErrorContains(t, s.Err(), `invalid column "date"`)
+	})
+
+	t.Run("Map requires a layout", func(t *testing.T) {
+		s := FixedWidth[map[string]string](strings.NewReader("abc"), nil)
+		assert.False(t, s.Next())
+		assert.ErrorContains(t, s.Err(), "a layout is required")
+	})
+
+	t.Run("Read error", func(t *testing.T) {
+		boom := errors.New("boom")
+
+		s := FixedWidth[fwMovement](&failingReader{err: boom}, nil)
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), boom)
+	})
+}
+
+// ExampleFixedWidth demonstrates parsing a fixed-width bank statement with struct tags.
+func ExampleFixedWidth() {
+	type movement struct {
+		Account string  `fw:"0,6"`
+		Concept string  `fw:"6,10"`
+		Amount  float64 `fw:"16,9"`
+	}
+
+	statement := strings.NewReader(
+		"ES0001Payroll     1500.00\n" +
+			"ES0001Rent        -750.50\n",
+	)
+
+	movements := FixedWidth[movement](statement, nil)
+	for movements.Next() {
+		m := movements.Data()
+		fmt.Printf("%s %-8s %8.2f\n", m.Account, m.Concept, m.Amount)
+	}
+	// Output:
+	// ES0001 Payroll   1500.00
+	// ES0001 Rent      -750.50
+}
@@ -0,0 +1,184 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"sync"
+)
+
+// InterleaveStream merges its inner streams concurrently, yielding items in
+// the order they arrive.
+//
+// Every inner stream is read by its own goroutine, which is the only one
+// touching it, so inner streams do not need to be safe for concurrent use.
+type InterleaveStream[T any] struct {
+	streams []ReadStream[T]
+	parent  context.Context
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	items  chan T
+	wg     sync.WaitGroup
+
+	// mu guards innerErr, written by the pumps.
+	mu       sync.Mutex
+	innerErr error
+
+	current T
+	err     error
+	started bool
+	done    bool
+	closed  bool
+}
+
+// Interleave creates a new ReadStream that merges streams as their items
+// arrive, e.g. several Channel streams fed by independent producers. The
+// relative order of items from the same stream is preserved.
+func Interleave[T any](streams ...ReadStream[T]) ReadStream[T] {
+	return InterleaveContext(context.Background(), streams...)
+}
+
+// InterleaveContext is like Interleave but stops once ctx is done, reporting
+// ctx.Err() through Err.
+//
+// The first inner error other than io.EOF stops every other stream and is
+// reported by Err.
+// Streams able to abort a blocking read, such as the ones returned by
+// Channel, are interrupted right away; any other stream is left alone until
+// its pending Next returns. Close waits for every goroutine to exit and
+// closes all the inner streams.
+func InterleaveContext[T any](ctx context.Context, streams ...ReadStream[T]) ReadStream[T] {
+	return &InterleaveStream[T]{
+		streams: streams,
+		parent:  ctx,
+	}
+}
+
+func (s *InterleaveStream[T]) start() {
+	s.started = true
+	s.ctx, s.cancel = context.WithCancel(s.parent)
+	s.items = make(chan T)
+
+	s.wg.Add(len(s.streams))
+
+	for _, stream := range s.streams {
+		if aware, ok := stream.(contextAware); ok {
+			aware.setContext(s.ctx)
+		}
+
+		go s.pump(stream)
+	}
+
+	go func() {
+		s.wg.Wait()
+		close(s.items)
+	}()
+}
+
+// pump forwards the items of a single inner stream.
+func (s *InterleaveStream[T]) pump(stream ReadStream[T]) {
+	defer s.wg.Done()
+
+	for stream.Next() {
+		select {
+		case s.items <- stream.Data():
+		case <-s.ctx.Done():
+			return
+		}
+	}
+
+	// errors caused by our own cancellation are not worth reporting, and
+	// io.EOF only ends this stream
+	err := stream.Err()
+	if err != nil && !errors.Is(err, io.EOF) && s.ctx.Err() == nil {
+		s.mu.Lock()
+		if s.innerErr == nil {
+			s.innerErr = err
+		}
+		s.mu.Unlock()
+
+		s.cancel()
+	}
+}
+
+func (s *InterleaveStream[T]) Next() bool {
+	if s.done {
+		return false
+	}
+
+	if !s.started {
+		s.start()
+	}
+
+	item, ok := <-s.items
+	if !ok {
+		s.finish()
+		return false
+	}
+
+	if s.ctx.Err() != nil {
+		s.finish()
+		return false
+	}
+
+	s.current = item
+	return true
+}
+
+// finish stops all goroutines and settles the final error of the stream.
+func (s *InterleaveStream[T]) finish() {
+	s.done = true
+	s.cancel()
+
+	for range s.items {
+	}
+
+	s.mu.Lock()
+	s.err = s.innerErr
+	s.mu.Unlock()
+
+	if s.err == nil && !s.closed {
+		s.err = s.parent.Err()
+	}
+}
+
+func (s *InterleaveStream[T]) Data() T {
+	return s.current
+}
+
+func (s *InterleaveStream[T]) Err() error {
+	return s.err
+}
+
+func (s *InterleaveStream[T]) Close() error {
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	if s.started && !s.done {
+		s.finish()
+	}
+
+	s.done = true
+
+	errs := make([]error, len(s.streams))
+	for i, stream := range s.streams {
+		errs[i] = stream.Close()
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *InterleaveStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *InterleaveStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[any] = new(InterleaveStream[any])
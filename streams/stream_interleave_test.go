@@ -0,0 +1,196 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInterleaveStream(t *testing.T) {
+	a := make(chan int)
+	b := make(chan int)
+
+	go func() {
+		defer close(a)
+		for _, v := range []int{1, 3, 5} {
+			a <- v
+		}
+	}()
+
+	go func() {
+		defer close(b)
+		for _, v := range []int{2, 4} {
+			b <- v
+		}
+	}()
+
+	result, err := Consume(Interleave(Channel(a), Channel(b), MemReader([]int{6}, nil)))
+	require.NoError(t, err)
+
+	var fromA []int
+	for _, v := range result {
+		if v%2 == 1 {
+			fromA = append(fromA, v)
+		}
+	}
+	assert.Equal(t, []int{1, 3, 5}, fromA, "order within a stream is preserved")
+
+	sort.Ints(result)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, result)
+}
+
+func TestInterleaveStream_ArrivalOrder(t *testing.T) {
+	slow := make(chan string)
+	fast := make(chan string, 1)
+	fast <- "fast"
+	close(fast)
+
+	s := Interleave(Channel(slow), Channel(fast))
+	defer s.Close()
+
+	require.True(t, s.Next())
+	assert.Equal(t, "fast", s.Data(), "a blocked stream must not hold back the others")
+
+	slow <- "slow"
+	require.True(t, s.Next())
+	assert.Equal(t, "slow", s.Data())
+
+	close(slow)
+	assert.False(t, s.Next())
+	assert.NoError(t, s.Err())
+}
+
+func TestInterleaveStream_ErrorStopsAll(t *testing.T) {
+	errRead := errors.New("read failed")
+	blocked := make(chan int)
+
+	s := Interleave(Channel(blocked), MemReader([]int{}, errRead))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for s.Next() {
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the error did not interrupt the blocked channel")
+	}
+
+	assert.ErrorIs(t, s.Err(), errRead)
+	assert.NoError(t, s.Close())
+}
+
+func TestInterleaveStream_Context(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	s := InterleaveContext(ctx, Channel(make(chan int)))
+
+	assert.False(t, s.Next())
+	assert.ErrorIs(t, s.Err(), context.DeadlineExceeded)
+}
+
+func TestInterleaveStream_InnerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+
+	s := Interleave(ChannelContext(ctx, ch), Channel(make(chan int)))
+
+	go func() { ch <- 1 }()
+
+	require.True(t, s.Next())
+	assert.Equal(t, 1, s.Data())
+
+	cancel()
+
+	done := make(chan bool)
+	go func() { done <- s.Next() }()
+
+	select {
+	case more := <-done:
+		assert.False(t, more)
+	case <-time.After(time.Second):
+		t.Fatal("cancelling the inner context did not stop the stream")
+	}
+
+	assert.ErrorIs(t, s.Err(), context.Canceled)
+	assert.NoError(t, s.Close())
+}
+
+func TestInterleaveStream_JSON(t *testing.T) {
+	blocked := make(chan int)
+	s := Interleave(jsonRows[int]("1\n2\n"), jsonRows[int]("3\n"), Channel(blocked))
+
+	var got []int
+	for range 3 {
+		require.True(t, s.Next())
+		got = append(got, s.Data())
+	}
+
+	sort.Ints(got)
+	assert.Equal(t, []int{1, 2, 3}, got, "the end of a JSON stream must not stop the others")
+
+	blocked <- 4
+	require.True(t, s.Next())
+	assert.Equal(t, 4, s.Data())
+
+	close(blocked)
+	assert.False(t, s.Next())
+	assert.NoError(t, s.Err())
+	assert.NoError(t, s.Close())
+}
+
+func TestInterleaveStream_Close(t *testing.T) {
+	blocked := track(Channel(make(chan int)))
+	ready := track[int](MemReader([]int{1, 2, 3}, nil))
+
+	s := Interleave[int](blocked, ready)
+	require.True(t, s.Next())
+
+	assert.NoError(t, s.Close())
+	assert.True(t, blocked.closed)
+	assert.True(t, ready.closed)
+	assert.False(t, s.Next())
+	assert.NoError(t, s.Err())
+}
+
+// ExampleInterleave demonstrates merging channel-backed streams as items arrive.
+func ExampleInterleave() {
+	orders := make(chan string)
+	refunds := make(chan string)
+
+	go func() {
+		defer close(orders)
+		orders <- "order #1"
+		orders <- "order #2"
+	}()
+
+	go func() {
+		defer close(refunds)
+		refunds <- "refund #1"
+	}()
+
+	stream := Interleave(Channel(orders), Channel(refunds))
+	defer stream.Close()
+
+	// Items arrive in any order, sort them for a stable output
+	events, _ := Consume(stream)
+	sort.Strings(events)
+
+	for _, event := range events {
+		fmt.Println(event)
+	}
+	// Output:
+	// order #1
+	// order #2
+	// refund #1
+}
@@ -0,0 +1,142 @@
+package streams
+
+import (
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/sonirico/vago/fp"
+)
+
+// HashJoinStream joins every item of the left stream with the items of the
+// right stream sharing its key, looked up in an in-memory hash table built
+// from the right stream.
+type HashJoinStream[L, R any, K comparable, V any] struct {
+	left     ReadStream[L]
+	right    ReadStream[R]
+	leftKey  func(L) K
+	rightKey func(R) K
+	joinFn   func(L, fp.Option[R]) V
+	kind     JoinKind
+	table    map[K][]R
+	item     L
+	matches  []R
+	current  V
+	err      error
+	started  bool
+}
+
+// HashJoin creates a new ReadStream that joins the left and right streams by
+// key, calling joinFn with every left item and its matching right item, or
+// with no right item if JoinLeft or JoinAnti yield it unmatched.
+//
+// The right stream is read into memory before the first item is yielded, so
+// it should be the smaller one, e.g. a dimension table enriching a stream of
+// facts. Left items are yielded in order, each once per match. Close closes
+// both streams.
+func HashJoin[L, R any, K comparable, V any](
+	left ReadStream[L],
+	right ReadStream[R],
+	leftKey func(L) K,
+	rightKey func(R) K,
+	joinFn func(L, fp.Option[R]) V,
+	opts ...JoinOpt,
+) ReadStream[V] {
+	optsDef := joinOpts{
+		kind: JoinInner,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	return &HashJoinStream[L, R, K, V]{
+		left:     left,
+		right:    right,
+		leftKey:  leftKey,
+		rightKey: rightKey,
+		joinFn:   joinFn,
+		kind:     optsDef.kind,
+	}
+}
+
+func (s *HashJoinStream[L, R, K, V]) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	if !s.started {
+		s.started = true
+
+		if s.err = s.build(); s.err != nil {
+			return false
+		}
+	}
+
+	if len(s.matches) > 0 {
+		s.current = s.joinFn(s.item, fp.Some(s.matches[0]))
+		s.matches = s.matches[1:]
+		return true
+	}
+
+	for s.left.Next() {
+		item := s.left.Data()
+		matches := s.table[s.leftKey(item)]
+
+		switch {
+		case len(matches) == 0 && s.kind != JoinInner:
+			s.current = s.joinFn(item, fp.None[R]())
+			return true
+		case len(matches) > 0 && s.kind != JoinAnti:
+			s.item = item
+			s.current = s.joinFn(item, fp.Some(matches[0]))
+			s.matches = matches[1:]
+			return true
+		}
+	}
+
+	if err := s.left.Err(); err != nil && !errors.Is(err, io.EOF) {
+		s.err = err
+	}
+
+	return false
+}
+
+// build reads the right stream into the hash table.
+func (s *HashJoinStream[L, R, K, V]) build() error {
+	s.table = make(map[K][]R)
+
+	for s.right.Next() {
+		item := s.right.Data()
+		key := s.rightKey(item)
+		s.table[key] = append(s.table[key], item)
+	}
+
+	if err := s.right.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *HashJoinStream[L, R, K, V]) Data() V {
+	return s.current
+}
+
+func (s *HashJoinStream[L, R, K, V]) Err() error {
+	return s.err
+}
+
+func (s *HashJoinStream[L, R, K, V]) Close() error {
+	return errors.Join(s.left.Close(), s.right.Close())
+}
+
+func (s *HashJoinStream[L, R, K, V]) Iter() iter.Seq[V] {
+	return Iter(s)
+}
+
+func (s *HashJoinStream[L, R, K, V]) Iter2() iter.Seq2[V, error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[any] = new(HashJoinStream[any, any, int, any])
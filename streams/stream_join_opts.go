@@ -0,0 +1,31 @@
+package streams
+
+// JoinKind selects which left items HashJoin yields.
+type JoinKind int
+
+const (
+	// JoinInner yields a left item once per matching right item.
+	JoinInner JoinKind = iota
+	// JoinLeft is like JoinInner, but also yields left items without a
+	// match, with no right item.
+	JoinLeft
+	// JoinAnti yields only left items without a match.
+	JoinAnti
+)
+
+type JoinOpt func(*joinOpts)
+
+type joinOpts struct {
+	kind JoinKind
+}
+
+func (fn JoinOpt) apply(o *joinOpts) {
+	fn(o)
+}
+
+// WithJoinKind sets the kind of join. Defaults to JoinInner.
+func WithJoinKind(kind JoinKind) JoinOpt {
+	return func(o *joinOpts) {
+		o.kind = kind
+	}
+}
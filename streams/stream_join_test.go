@@ -0,0 +1,128 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sonirico/vago/fp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type joinTrade struct {
+	ID     int
+	UserID int
+}
+
+type joinUser struct {
+	ID   int
+	Name string
+}
+
+func TestHashJoinStream(t *testing.T) {
+	trades := []joinTrade{{1, 10}, {2, 20}, {3, 10}, {4, 30}}
+	users := []joinUser{{10, "ada"}, {20, "bob"}, {20, "bea"}}
+
+	describe := func(trade joinTrade, user fp.Option[joinUser]) string {
+		if u, ok := user.Unwrap(); ok {
+			return fmt.Sprintf("%d:%s", trade.ID, u.Name)
+		}
+		return fmt.Sprintf("%d:-", trade.ID)
+	}
+
+	tests := []struct {
+		name     string
+		kind     JoinKind
+		expected []string
+	}{
+		{"Inner", JoinInner, []string{"1:ada", "2:bob", "2:bea", "3:ada"}},
+		{"Left", JoinLeft, []string{"1:ada", "2:bob", "2:bea", "3:ada", "4:-"}},
+		{"Anti", JoinAnti, []string{"4:-"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := HashJoin(
+				MemReader(trades, nil),
+				MemReader(users, nil),
+				func(t joinTrade) int { return t.UserID },
+				func(u joinUser) int { return u.ID },
+				describe,
+				WithJoinKind(tt.kind),
+			)
+
+			result, err := Consume(s)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+
+	t.Run("Right error", func(t *testing.T) {
+		boom := errors.New("boom")
+
+		s := HashJoin(
+			MemReader(trades, nil),
+			MemReader(users, boom),
+			func(t joinTrade) int { return t.UserID },
+			func(u joinUser) int { return u.ID },
+			describe,
+		)
+
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), boom)
+	})
+
+	t.Run("Close closes both streams", func(t *testing.T) {
+		left, right := track(MemReader(trades, nil)), track(MemReader(users, nil))
+
+		s := HashJoin[joinTrade, joinUser](
+			left, right,
+			func(t joinTrade) int { return t.UserID },
+			func(u joinUser) int { return u.ID },
+			describe,
+		)
+
+		require.NoError(t, s.Close())
+		assert.True(t, left.closed)
+		assert.True(t, right.closed)
+	})
+}
+
+// ExampleHashJoin demonstrates enriching a stream of trades with users.
+func ExampleHashJoin() {
+	type trade struct {
+		ID     int
+		UserID int
+	}
+
+	type user struct {
+		ID   int
+		Name string
+	}
+
+	trades := MemReader([]trade{{1, 10}, {2, 20}, {3, 30}}, nil)
+	users := MemReader([]user{{10, "ada"}, {20, "bob"}}, nil)
+
+	joined := HashJoin(trades, users,
+		func(t trade) int { return t.UserID },
+		func(u user) int { return u.ID },
+		func(t trade, u fp.Option[user]) string {
+			name := "unknown"
+			if u, ok := u.Unwrap(); ok {
+				name = u.Name
+			}
+			return fmt.Sprintf("trade %d by %s", t.ID, name)
+		},
+		WithJoinKind(JoinLeft),
+	)
+
+	result, _ := Consume(joined)
+	for _, line := range result {
+		fmt.Println(line)
+	}
+	// Output:
+	// trade 1 by ada
+	// trade 2 by bob
+	// trade 3 by unknown
+}
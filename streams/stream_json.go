@@ -1,6 +1,7 @@
 package streams
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -8,6 +9,8 @@ import (
 )
 
 type JSONEachRowStream[T any] struct {
+	ctx context.Context
+
 	current T
 
 	r io.ReadCloser
@@ -18,7 +21,9 @@ type JSONEachRowStream[T any] struct {
 }
 
 func (s *JSONEachRowStream[T]) Next() bool {
-	s.err = s.decoder.Decode(&s.current)
+	if s.err = s.ctx.Err(); s.err == nil {
+		s.err = s.decoder.Decode(&s.current)
+	}
 
 	if s.err != nil {
 		if errClose := s.r.Close(); errClose != nil {
@@ -35,6 +40,12 @@ func (s *JSONEachRowStream[T]) Data() T {
 	return s.current
 }
 
+// Offset returns the number of bytes consumed from the reader, up to the end
+// of the current row.
+func (s *JSONEachRowStream[T]) Offset() int64 {
+	return s.decoder.InputOffset()
+}
+
 func (s *JSONEachRowStream[T]) Err() error {
 	return s.err
 }
@@ -55,7 +66,14 @@ func (s *JSONEachRowStream[T]) Iter2() iter.Seq2[T, error] {
 // The stream decodes JSON objects from the input, where each object represents a row.
 // This is useful for processing JSON data in a row-oriented manner, such as reading
 func JSON[T any](r io.ReadCloser) *JSONEachRowStream[T] {
+	return JSONContext[T](context.Background(), r)
+}
+
+// JSONContext is like JSON but stops decoding once ctx is done, reporting
+// ctx.Err() through Err.
+func JSONContext[T any](ctx context.Context, r io.ReadCloser) *JSONEachRowStream[T] {
 	return &JSONEachRowStream[T]{
+		ctx:     ctx,
 		r:       r,
 		decoder: json.NewDecoder(r),
 	}
@@ -0,0 +1,231 @@
+package streams
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+var ErrJSONPathNotFound = errors.New("json path not found")
+
+// JSONArrayStream decodes the elements of a JSON array one at a time, so only
+// the current element is held in memory. It can read back the output of
+// PipeJSON, or arrays nested in larger documents such as paginated API
+// payloads.
+type JSONArrayStream[T any] struct {
+	ctx     context.Context
+	r       io.ReadCloser
+	decoder *json.Decoder
+	path    string
+	current T
+	err     error
+	started bool
+	done    bool
+}
+
+// JSONArray creates a new JSONArrayStream that reads the elements of the
+// top-level JSON array in r, or of the array found at the path given with
+// WithJSONArrayPath. A null value at the path yields an empty stream.
+func JSONArray[T any](r io.ReadCloser, opts ...JSONArrayOpt) *JSONArrayStream[T] {
+	optsDef := jsonArrayOpts{
+		ctx:  context.Background(),
+		path: "$",
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	return &JSONArrayStream[T]{
+		ctx:     optsDef.ctx,
+		r:       r,
+		decoder: json.NewDecoder(r),
+		path:    optsDef.path,
+	}
+}
+
+func (s *JSONArrayStream[T]) Next() bool {
+	if s.done {
+		return false
+	}
+
+	if err := s.ctx.Err(); err != nil {
+		return s.fail(err)
+	}
+
+	if !s.started {
+		s.started = true
+
+		found, err := s.seek()
+		if err != nil {
+			return s.fail(err)
+		}
+		if !found {
+			s.done = true
+			return false
+		}
+	}
+
+	if !s.decoder.More() {
+		s.done = true
+
+		if _, err := s.decoder.Token(); err != nil {
+			return s.fail(err)
+		}
+		return false
+	}
+
+	var value T
+	if err := s.decoder.Decode(&value); err != nil {
+		return s.fail(err)
+	}
+
+	s.current = value
+	return true
+}
+
+func (s *JSONArrayStream[T]) fail(err error) bool {
+	s.err = err
+	s.done = true
+	return false
+}
+
+// seek walks the document down to the opening bracket of the array at the
+// configured path. It returns false when the value at the path is null.
+func (s *JSONArrayStream[T]) seek() (bool, error) {
+	segments, err := parseJSONPath(s.path)
+	if err != nil {
+		return false, err
+	}
+
+	for _, segment := range segments {
+		if err := s.expectDelim('{'); err != nil {
+			return false, fmt.Errorf("json array %s: %w", s.path, err)
+		}
+
+		if err := s.seekKey(segment); err != nil {
+			return false, err
+		}
+	}
+
+	tok, err := s.decoder.Token()
+	if err != nil {
+		return false, err
+	}
+
+	if tok == nil {
+		return false, nil
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return false, fmt.Errorf("json array %s: expected array, got %v", s.path, tok)
+	}
+
+	return true, nil
+}
+
+// seekKey advances the decoder to the value of key in the current object,
+// skipping every preceding member token by token.
+func (s *JSONArrayStream[T]) seekKey(key string) error {
+	for s.decoder.More() {
+		tok, err := s.decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		if tok == key {
+			return nil
+		}
+
+		if err := s.skipValue(); err != nil {
+			return err
+		}
+	}
+
+	return fmt.Errorf("%w: %s", ErrJSONPathNotFound, s.path)
+}
+
+// skipValue discards the next value without buffering it.
+func (s *JSONArrayStream[T]) skipValue() error {
+	depth := 0
+
+	for {
+		tok, err := s.decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		if delim, ok := tok.(json.Delim); ok {
+			switch delim {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+		}
+
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func (s *JSONArrayStream[T]) expectDelim(want json.Delim) error {
+	tok, err := s.decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %v, got %v", want, tok)
+	}
+
+	return nil
+}
+
+func (s *JSONArrayStream[T]) Data() T {
+	return s.current
+}
+
+func (s *JSONArrayStream[T]) Err() error {
+	return s.err
+}
+
+func (s *JSONArrayStream[T]) Close() error {
+	return s.r.Close()
+}
+
+func (s *JSONArrayStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *JSONArrayStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+// parseJSONPath splits a path such as `$.data.items` into its keys.
+func parseJSONPath(path string) ([]string, error) {
+	if path == "" || path == "$" {
+		return nil, nil
+	}
+
+	rest, ok := strings.CutPrefix(path, "$.")
+	if !ok {
+		return nil, fmt.Errorf("invalid json path %q: must start with $", path)
+	}
+
+	segments := strings.Split(rest, ".")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, fmt.Errorf("invalid json path %q: empty key", path)
+		}
+	}
+
+	return segments, nil
+}
+
+var _ ReadStream[any] = new(JSONArrayStream[any])
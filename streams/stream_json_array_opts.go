@@ -0,0 +1,31 @@
+package streams
+
+import "context"
+
+type JSONArrayOpt func(*jsonArrayOpts)
+
+type jsonArrayOpts struct {
+	ctx  context.Context
+	path string
+}
+
+func (fn JSONArrayOpt) apply(o *jsonArrayOpts) {
+	fn(o)
+}
+
+// WithJSONArrayPath points the stream to an array nested in the document,
+// using a dot separated path rooted at `$`, e.g. `$.data.items`. Defaults to
+// `$`, the top-level value.
+func WithJSONArrayPath(path string) JSONArrayOpt {
+	return func(o *jsonArrayOpts) {
+		o.path = path
+	}
+}
+
+// WithJSONArrayContext stops decoding once ctx is done, reporting ctx.Err()
+// through Err.
+func WithJSONArrayContext(ctx context.Context) JSONArrayOpt {
+	return func(o *jsonArrayOpts) {
+		o.ctx = ctx
+	}
+}
@@ -0,0 +1,161 @@
+package streams
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonArrayItem struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestJSONArrayStream(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		path        string
+		expected    []jsonArrayItem
+		expectedErr error
+		errContains string
+	}{
+		{
+			name:     "Top-level array",
+			input:    `[{"id":1,"name":"a"}, {"id":2,"name":"b"}]`,
+			expected: []jsonArrayItem{{1, "a"}, {2, "b"}},
+		},
+		{
+			name:     "Empty array",
+			input:    ` [ ] `,
+			expected: nil,
+		},
+		{
+			name: "Nested path skipping siblings",
+			input: `{
+				"meta": {"page": 1, "tags": [[1], {"x": [2]}]},
+				"data": {"total": 2, "items": [{"id":3,"name":"c"},{"id":4,"name":"d"}], "next": null}
+			}`,
+			path:     "$.data.items",
+			expected: []jsonArrayItem{{3, "c"}, {4, "d"}},
+		},
+		{
+			name:     "Null at path",
+			input:    `{"data": {"items": null}}`,
+			path:     "$.data.items",
+			expected: nil,
+		},
+		{
+			name:        "Missing path",
+			input:       `{"data": {"rows": []}}`,
+			path:        "$.data.items",
+			expectedErr: ErrJSONPathNotFound,
+		},
+		{
+			name:        "Not an array",
+			input:       `{"data": {"items": {"id": 1}}}`,
+			path:        "$.data.items",
+			errContains: "expected array",
+		},
+		{
+			name:        "Invalid path",
+			input:       `[]`,
+			path:        "data.items",
+			errContains: "must start with $",
+		},
+		{
+			name:        "Malformed element",
+			input:       `[{"id":1,"name":"a"}, {"id":"two"}]`,
+			expected:    []jsonArrayItem{{1, "a"}},
+			errContains: "cannot unmarshal",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var opts []JSONArrayOpt
+			if tc.path != "" {
+				opts = append(opts, WithJSONArrayPath(tc.path))
+			}
+
+			s := JSONArray[jsonArrayItem](io.NopCloser(strings.NewReader(tc.input)), opts...)
+
+			var got []jsonArrayItem
+			for s.Next() {
+				got = append(got, s.Data())
+			}
+
+			assert.Equal(t, tc.expected, got)
+
+			switch {
+			case tc.expectedErr != nil:
+				assert.ErrorIs(t, s.Err(), tc.expectedErr)
+			case tc.errContains != "":
+				assert.ErrorContains(t, s.Err(), tc.errContains)
+			default:
+				assert.NoError(t, s.Err())
+			}
+
+			assert.False(t, s.Next())
+			assert.NoError(t, s.Close())
+		})
+	}
+}
+
+func TestJSONArrayStream_ReadsPipeJSON(t *testing.T) {
+	items := []jsonArrayItem{{1, "a"}, {2, "b"}, {3, "c"}}
+
+	var buf bytes.Buffer
+	_, err := PipeJSON(MemReader(items, nil), &buf)
+	require.NoError(t, err)
+
+	result, err := Consume(JSONArray[jsonArrayItem](io.NopCloser(&buf)))
+	require.NoError(t, err)
+	assert.Equal(t, items, result)
+}
+
+func TestJSONArrayStream_Context(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := JSONArray[int](
+		io.NopCloser(strings.NewReader(`[1,2,3]`)),
+		WithJSONArrayContext(ctx),
+	)
+
+	assert.False(t, s.Next())
+	assert.True(t, errors.Is(s.Err(), context.Canceled))
+}
+
+// ExampleJSONArray demonstrates streaming the items of an array nested in an API payload.
+func ExampleJSONArray() {
+	payload := `{"page": 1, "data": {"items": [
+		{"name": "Alice", "age": 25},
+		{"name": "Bob", "age": 30}
+	]}}`
+
+	type Person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	stream := JSONArray[Person](
+		io.NopCloser(strings.NewReader(payload)),
+		WithJSONArrayPath("$.data.items"),
+	)
+
+	for stream.Next() {
+		person := stream.Data()
+		fmt.Printf("Person: %s, Age: %d\n", person.Name, person.Age)
+	}
+	// Output:
+	// Person: Alice, Age: 25
+	// Person: Bob, Age: 30
+}
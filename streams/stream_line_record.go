@@ -0,0 +1,135 @@
+package streams
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+type (
+	// LineError reports a line that could not be parsed into a record.
+	LineError struct {
+		Line int
+		Err  error
+	}
+
+	// LineRecordStream parses every non blank line of a reader into a
+	// record, as done by FixedWidth and Logfmt.
+	LineRecordStream[T any] struct {
+		opts     lineRecordOpts
+		original io.Reader
+		reader   *bufio.Reader
+		parse    func(string) (T, error)
+		line     int
+		current  T
+		err      error
+		done     bool
+	}
+)
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+func newLineRecordStream[T any](
+	r io.Reader,
+	parse func(string) (T, error),
+	opts []LineRecordOpt,
+) *LineRecordStream[T] {
+	optsDef := lineRecordOpts{
+		ctx: context.Background(),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	return &LineRecordStream[T]{
+		opts:     optsDef,
+		original: r,
+		reader:   bufio.NewReader(r),
+		parse:    parse,
+	}
+}
+
+func (s *LineRecordStream[T]) Next() bool {
+	for !s.done && s.err == nil {
+		if s.err = s.opts.ctx.Err(); s.err != nil {
+			return false
+		}
+
+		line, err := s.reader.ReadString('\n')
+		if err != nil {
+			s.done = true
+
+			if !errors.Is(err, io.EOF) {
+				s.err = err
+				return false
+			}
+		}
+
+		if line == "" && s.done {
+			return false
+		}
+
+		s.line++
+
+		line = strings.TrimRight(line, "\r\n")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		record, err := s.parse(line)
+		if err == nil {
+			s.current = record
+			return true
+		}
+
+		lineErr := &LineError{Line: s.line, Err: err}
+
+		if !s.opts.skip {
+			s.err = lineErr
+			return false
+		}
+
+		if s.opts.onSkip != nil {
+			s.opts.onSkip(lineErr)
+		}
+	}
+
+	return false
+}
+
+func (s *LineRecordStream[T]) Data() T {
+	return s.current
+}
+
+func (s *LineRecordStream[T]) Err() error {
+	return s.err
+}
+
+// Close closes the reader if it implements io.Closer
+func (s *LineRecordStream[T]) Close() error {
+	if closer, ok := s.original.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (s *LineRecordStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *LineRecordStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[any] = new(LineRecordStream[any])
@@ -0,0 +1,33 @@
+package streams
+
+import "context"
+
+type LineRecordOpt func(*lineRecordOpts)
+
+type lineRecordOpts struct {
+	ctx  context.Context
+	skip bool
+	// onSkip is called with every skipped malformed line, if set.
+	onSkip func(*LineError)
+}
+
+func (fn LineRecordOpt) apply(o *lineRecordOpts) {
+	fn(o)
+}
+
+// WithLineRecordContext stops reading once ctx is done, reporting ctx.Err()
+// through Err.
+func WithLineRecordContext(ctx context.Context) LineRecordOpt {
+	return func(o *lineRecordOpts) {
+		o.ctx = ctx
+	}
+}
+
+// WithLineRecordSkipErrors skips malformed lines instead of stopping the
+// stream, calling onSkip, if not nil, with the error of each one.
+func WithLineRecordSkipErrors(onSkip func(*LineError)) LineRecordOpt {
+	return func(o *lineRecordOpts) {
+		o.skip = true
+		o.onSkip = onSkip
+	}
+}
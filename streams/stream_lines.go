@@ -2,14 +2,17 @@ package streams
 
 import (
 	"bufio"
+	"context"
 	"io"
 )
 
 // LineReaderStream reads lines as strings instead of bytes
 type LineReaderStream struct {
+	ctx      context.Context
 	original io.Reader
 	reader   *bufio.Reader
 	current  string
+	offset   int64
 	err      error
 	closed   bool
 }
@@ -20,7 +23,12 @@ func (r *LineReaderStream) Next() bool {
 		return false
 	}
 
+	if r.err = r.ctx.Err(); r.err != nil {
+		return false
+	}
+
 	line, err := r.reader.ReadString('\n')
+	r.offset += int64(len(line))
 	if err != nil {
 		if err == io.EOF {
 			if len(line) > 0 {
@@ -65,6 +73,12 @@ func (r *LineReaderStream) Data() string {
 	return r.current
 }
 
+// Offset returns the number of bytes consumed from the reader, up to and
+// including the current line and its line ending.
+func (r *LineReaderStream) Offset() int64 {
+	return r.offset
+}
+
 // Err returns the current error state
 func (r *LineReaderStream) Err() error {
 	// Don't report EOF as an error - it's the normal end of stream
@@ -90,7 +104,14 @@ func (r *LineReaderStream) Close() error {
 
 // Lines creates a new ReadStream that reads lines as strings from an io.Reader
 func Lines(reader io.Reader) ReadStream[string] {
+	return LinesContext(context.Background(), reader)
+}
+
+// LinesContext is like Lines but stops reading once ctx is done, reporting
+// ctx.Err() through Err.
+func LinesContext(ctx context.Context, reader io.Reader) ReadStream[string] {
 	return &LineReaderStream{
+		ctx:      ctx,
 		original: reader,
 		reader:   bufio.NewReader(reader),
 	}
@@ -0,0 +1,156 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// logfmtField is a key decoded into a struct field.
+type logfmtField struct {
+	index  []int
+	layout string
+}
+
+// Logfmt creates a new ReadStream that parses logfmt lines, i.e. space
+// separated key=value pairs where values containing spaces are double
+// quoted, as in:
+//
+//	level=info msg="request served" status=200 cached
+//
+// Keys without a value, as cached above, are read as "true". Blank lines are
+// skipped.
+//
+// T may be a map[string]string or a struct, whose fields are matched to keys
+// by their `logfmt:"key"` tag, or by field name ignoring case, and decoded as
+// with CSV. Unknown keys are ignored.
+//
+// A line that cannot be parsed stops the stream with a *LineError, unless
+// WithLineRecordSkipErrors is given.
+func Logfmt[T any](r io.Reader, opts ...LineRecordOpt) ReadStream[T] {
+	parse, err := logfmtParser[T]()
+	stream := newLineRecordStream(r, parse, opts)
+	stream.err = err
+	return stream
+}
+
+func logfmtParser[T any]() (func(string) (T, error), error) {
+	t := reflect.TypeFor[T]()
+
+	if t == reflect.TypeFor[map[string]string]() {
+		return func(line string) (T, error) {
+			record := make(map[string]string)
+
+			err := parseLogfmt(line, func(key, value string) error {
+				record[key] = value
+				return nil
+			})
+
+			return any(record).(T), err
+		}, nil
+	}
+
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("logfmt: type must be map[string]string or a struct, got %s", t)
+	}
+
+	fields := make(map[string]logfmtField)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		key := f.Tag.Get("logfmt")
+		if key == "-" || !f.IsExported() {
+			continue
+		}
+		if key == "" {
+			key = f.Name
+		}
+
+		fields[strings.ToLower(key)] = logfmtField{index: f.Index, layout: f.Tag.Get("layout")}
+	}
+
+	return func(line string) (T, error) {
+		var value T
+		v := reflect.ValueOf(&value).Elem()
+
+		err := parseLogfmt(line, func(key, cell string) error {
+			f, ok := fields[strings.ToLower(key)]
+			if !ok {
+				return nil
+			}
+
+			if err := setCSVField(v.FieldByIndex(f.index), cell, f.layout); err != nil {
+				return fmt.Errorf("key %s: %w", key, err)
+			}
+			return nil
+		})
+
+		return value, err
+	}, nil
+}
+
+// parseLogfmt calls set with every key and value pair of line.
+func parseLogfmt(line string, set func(key, value string) error) error {
+	for {
+		line = strings.TrimLeft(line, " \t")
+		if line == "" {
+			return nil
+		}
+
+		end := strings.IndexAny(line, "= \t")
+		if end < 0 {
+			end = len(line)
+		}
+
+		key := line[:end]
+		if key == "" {
+			return errors.New("missing key")
+		}
+
+		line = line[end:]
+
+		if !strings.HasPrefix(line, "=") {
+			if err := set(key, "true"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		line = line[1:]
+
+		var value string
+
+		if strings.HasPrefix(line, `"`) {
+			quoted, err := strconv.QuotedPrefix(line)
+			if err != nil {
+				return fmt.Errorf("key %s: unterminated quoted value", key)
+			}
+
+			line = line[len(quoted):]
+			if line != "" && line[0] != ' ' && line[0] != '\t' {
+				return fmt.Errorf("key %s: unexpected %q after quoted value", key, line[0])
+			}
+
+			value, _ = strconv.Unquote(quoted)
+		} else {
+			end = strings.IndexAny(line, " \t")
+			if end < 0 {
+				end = len(line)
+			}
+
+			value, line = line[:end], line[end:]
+
+			if strings.ContainsAny(value, `="`) {
+				return fmt.Errorf("key %s: unexpected character in value %q", key, value)
+			}
+		}
+
+		if err := set(key, value); err != nil {
+			return err
+		}
+	}
+}
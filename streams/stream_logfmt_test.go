@@ -0,0 +1,121 @@
+package streams
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type logfmtEntry struct {
+	Time    time.Time     `logfmt:"ts"`
+	Level   string        `logfmt:"level"`
+	Message string        `logfmt:"msg"`
+	Status  int           `logfmt:"status"`
+	Latency time.Duration `logfmt:"-"`
+	Cached  bool
+}
+
+func TestLogfmt(t *testing.T) {
+	const input = `ts=2024-01-05T10:00:00Z level=info msg="request served" status=200 cached
+level=warn msg="quote \" inside" unknown=1
+
+level=error  msg=failed status=500`
+
+	t.Run("Into maps", func(t *testing.T) {
+		result, err := Consume(Logfmt[map[string]string](strings.NewReader(input)))
+		require.NoError(t, err)
+
+		assert.Equal(t, []map[string]string{
+			{"ts": "2024-01-05T10:00:00Z", "level": "info", "msg": "request served", "status": "200", "cached": "true"},
+			{"level": "warn", "msg": `quote " inside`, "unknown": "1"},
+			{"level": "error", "msg": "failed", "status": "500"},
+		}, result)
+	})
+
+	t.Run("Into structs", func(t *testing.T) {
+		result, err := Consume(Logfmt[logfmtEntry](strings.NewReader(input)))
+		require.NoError(t, err)
+
+		assert.Equal(t, []logfmtEntry{
+			{
+				Time:    time.Date(2024, 1, 5, 10, 0, 0, 0, time.UTC),
+				Level:   "info",
+				Message: "request served",
+				Status:  200,
+				Cached:  true,
+			},
+			{Level: "warn", Message: `quote " inside`},
+			{Level: "error", Message: "failed", Status: 500},
+		}, result)
+	})
+
+	tests := []struct {
+		name string
+		line string
+		err  string
+	}{
+		{"Unterminated quote", `msg="oops`, "line 2: key msg: unterminated quoted value"},
+		{"Missing key", `=value`, "line 2: missing key"},
+		{"Junk after quote", `msg="a"b`, `line 2: key msg: unexpected 'b' after quoted value`},
+		{"Stray quote", `msg=a"b`, `line 2: key msg: unexpected character in value "a\"b"`},
+		{"Invalid field", `status=ok`, `line 2: key status: strconv.ParseInt: parsing "ok": invalid syntax`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := Logfmt[logfmtEntry](strings.NewReader("level=info\n" + tt.line + "\nlevel=debug"))
+
+			assert.True(t, s.Next())
+			assert.False(t, s.Next())
+			assert.EqualError(t, s.Err(), tt.err)
+		})
+	}
+
+	t.Run("Skip malformed lines", func(t *testing.T) {
+		var skipped []error
+
+		s := Logfmt[map[string]string](
+			strings.NewReader("a=1\nb=\"2\nc=3"),
+			WithLineRecordSkipErrors(func(err *LineError) { skipped = append(skipped, err) }),
+		)
+
+		result, err := Consume(s)
+		require.NoError(t, err)
+		assert.Equal(t, []map[string]string{{"a": "1"}, {"c": "3"}}, result)
+		require.Len(t, skipped, 1)
+		assert.EqualError(t, skipped[0], "line 2: key b: unterminated quoted value")
+	})
+
+	t.Run("Unsupported type", func(t *testing.T) {
+		s := Logfmt[int](strings.NewReader("a=1"))
+		assert.False(t, s.Next())
+		assert.ErrorContains(t, s.Err(), "type must be map[string]string or a struct")
+	})
+}
+
+// ExampleLogfmt demonstrates parsing key=value log lines into structs.
+func ExampleLogfmt() {
+	type entry struct {
+		Level   string `logfmt:"level"`
+		Message string `logfmt:"msg"`
+		Status  int    `logfmt:"status"`
+	}
+
+	logs := strings.NewReader(
+		`level=info msg="request served" status=200` + "\n" +
+			`level=error msg=timeout status=504` + "\n",
+	)
+
+	entries := Logfmt[entry](logs)
+	for entries.Next() {
+		e := entries.Data()
+		fmt.Printf("[%s] %s (%d)\n", e.Level, e.Message, e.Status)
+	}
+	// Output:
+	// [info] request served (200)
+	// [error] timeout (504)
+}
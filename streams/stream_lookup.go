@@ -0,0 +1,129 @@
+package streams
+
+import (
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/sonirico/vago/fp"
+	"github.com/sonirico/vago/tuples"
+)
+
+// LookupStream enriches the items of the inner stream with values fetched in
+// batches by key.
+type LookupStream[T any, K comparable, V any] struct {
+	inner       ReadStream[T]
+	keyFn       func(T) K
+	batchLookup func([]K) (map[K]V, error)
+	batchSize   int
+	batch       []T
+	values      map[K]V
+	pos         int
+	current     tuples.Tuple2[T, fp.Option[V]]
+	err         error
+	done        bool
+}
+
+// Lookup creates a new ReadStream that pairs every item of the inner stream
+// with the value batchLookup returns for its key, or none if it returns no
+// value for it.
+//
+// Items are read batchSize at a time and batchLookup is called once per
+// batch with its distinct keys, in order of appearance, e.g. to resolve them
+// with a single WHERE id IN query instead of one query per item. An error
+// from batchLookup stops the stream and is reported by Err.
+func Lookup[T any, K comparable, V any](
+	inner ReadStream[T],
+	keyFn func(T) K,
+	batchLookup func([]K) (map[K]V, error),
+	batchSize int,
+) ReadStream[tuples.Tuple2[T, fp.Option[V]]] {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	return &LookupStream[T, K, V]{
+		inner:       inner,
+		keyFn:       keyFn,
+		batchLookup: batchLookup,
+		batchSize:   batchSize,
+		batch:       make([]T, 0, batchSize),
+	}
+}
+
+func (s *LookupStream[T, K, V]) Next() bool {
+	if s.pos >= len(s.batch) && !s.fill() {
+		return false
+	}
+
+	item := s.batch[s.pos]
+	s.pos++
+
+	value, ok := s.values[s.keyFn(item)]
+	s.current = tuples.Tuple2[T, fp.Option[V]]{V1: item, V2: fp.OptionFromTuple(value, ok)}
+	return true
+}
+
+// fill reads the next batch and looks up its keys.
+func (s *LookupStream[T, K, V]) fill() bool {
+	if s.done || s.err != nil {
+		return false
+	}
+
+	clear(s.batch)
+	s.batch = s.batch[:0]
+	s.pos = 0
+
+	for len(s.batch) < s.batchSize && s.inner.Next() {
+		s.batch = append(s.batch, s.inner.Data())
+	}
+
+	if len(s.batch) < s.batchSize {
+		s.done = true
+
+		if err := s.inner.Err(); err != nil && !errors.Is(err, io.EOF) {
+			s.err = err
+			return false
+		}
+	}
+
+	if len(s.batch) == 0 {
+		return false
+	}
+
+	seen := make(map[K]struct{}, len(s.batch))
+	keys := make([]K, 0, len(s.batch))
+
+	for _, item := range s.batch {
+		key := s.keyFn(item)
+		if _, ok := seen[key]; !ok {
+			seen[key] = struct{}{}
+			keys = append(keys, key)
+		}
+	}
+
+	s.values, s.err = s.batchLookup(keys)
+	return s.err == nil
+}
+
+func (s *LookupStream[T, K, V]) Data() tuples.Tuple2[T, fp.Option[V]] {
+	return s.current
+}
+
+func (s *LookupStream[T, K, V]) Err() error {
+	return s.err
+}
+
+func (s *LookupStream[T, K, V]) Close() error {
+	return s.inner.Close()
+}
+
+func (s *LookupStream[T, K, V]) Iter() iter.Seq[tuples.Tuple2[T, fp.Option[V]]] {
+	return Iter(s)
+}
+
+func (s *LookupStream[T, K, V]) Iter2() iter.Seq2[tuples.Tuple2[T, fp.Option[V]], error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[tuples.Tuple2[any, fp.Option[any]]] = new(LookupStream[any, int, any])
@@ -0,0 +1,109 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupStream(t *testing.T) {
+	names := map[int]string{10: "ada", 20: "bob"}
+
+	t.Run("Batches distinct keys", func(t *testing.T) {
+		var calls [][]int
+
+		lookup := func(ids []int) (map[int]string, error) {
+			calls = append(calls, ids)
+
+			result := make(map[int]string)
+			for _, id := range ids {
+				if name, ok := names[id]; ok {
+					result[id] = name
+				}
+			}
+			return result, nil
+		}
+
+		s := Lookup(MemReader([]int{10, 20, 10, 30, 20}, nil), func(id int) int { return id }, lookup, 3)
+
+		var got []string
+		for s.Next() {
+			item := s.Data()
+			got = append(got, fmt.Sprintf("%d=%s", item.V1, item.V2.UnwrapOr("?")))
+		}
+
+		require.NoError(t, s.Err())
+		assert.Equal(t, []string{"10=ada", "20=bob", "10=ada", "30=?", "20=bob"}, got)
+		assert.Equal(t, [][]int{{10, 20}, {30, 20}}, calls)
+	})
+
+	t.Run("Lookup error", func(t *testing.T) {
+		boom := errors.New("boom")
+
+		s := Lookup(MemReader([]int{1, 2}, nil), func(id int) int { return id },
+			func([]int) (map[int]string, error) { return nil, boom }, 10)
+
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), boom)
+	})
+
+	t.Run("Inner error", func(t *testing.T) {
+		boom := errors.New("boom")
+		called := false
+
+		s := Lookup(MemReader([]int(nil), boom), func(id int) int { return id },
+			func([]int) (map[int]string, error) { called = true; return nil, nil }, 10)
+
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), boom)
+		assert.False(t, called)
+	})
+
+	t.Run("Empty stream", func(t *testing.T) {
+		called := false
+
+		s := Lookup(MemReader([]int(nil), nil), func(id int) int { return id },
+			func([]int) (map[int]string, error) { called = true; return nil, nil }, 10)
+
+		assert.False(t, s.Next())
+		assert.NoError(t, s.Err())
+		assert.False(t, called)
+	})
+}
+
+// ExampleLookup demonstrates enriching items with a batched lookup instead of one call per item.
+func ExampleLookup() {
+	prices := map[string]float64{"BTC": 65000, "ETH": 3500}
+
+	fetchPrices := func(symbols []string) (map[string]float64, error) {
+		fmt.Println("lookup", symbols)
+		result := make(map[string]float64)
+		for _, s := range symbols {
+			if p, ok := prices[s]; ok {
+				result[s] = p
+			}
+		}
+		return result, nil
+	}
+
+	orders := MemReader([]string{"BTC", "ETH", "BTC", "DOGE"}, nil)
+	enriched := Lookup(orders, func(s string) string { return s }, fetchPrices, 100)
+
+	for enriched.Next() {
+		item := enriched.Data()
+		if price, ok := item.V2.Unwrap(); ok {
+			fmt.Println(item.V1, price)
+		} else {
+			fmt.Println(item.V1, "no price")
+		}
+	}
+	// Output:
+	// lookup [BTC ETH DOGE]
+	// BTC 65000
+	// ETH 3500
+	// BTC 65000
+	// DOGE no price
+}
@@ -0,0 +1,174 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+)
+
+type (
+	// Failed is an item whose mapping failed, as routed to the dead-letter
+	// stream of MapRetry.
+	Failed[T any] struct {
+		Item     T
+		Err      error
+		Attempts int
+	}
+
+	// MapRetryStream maps the items of the inner stream, retrying failed
+	// calls to the mapper with exponential backoff.
+	MapRetryStream[T, V any] struct {
+		inner   ReadStream[T]
+		mapper  func(T) (V, error)
+		opts    mapRetryOpts[T]
+		current V
+		err     error
+		done    bool
+	}
+)
+
+// MapRetry creates a new ReadStream like MapErr, but a failing mapper call is
+// retried with exponential backoff as long as the error is retryable and
+// attempts remain.
+//
+// Once an item ultimately fails, the stream stops and Err reports the error,
+// unless a dead-letter stream was given with WithMapRetryDeadLetter, in which
+// case the item is routed there and the stream continues. The dead-letter
+// stream is flushed once the stream ends or is closed.
+func MapRetry[T, V any](inner ReadStream[T], mapper func(T) (V, error), opts ...MapRetryOpt[T]) ReadStream[V] {
+	optsDef := mapRetryOpts[T]{
+		ctx:        context.Background(),
+		attempts:   3,
+		backoff:    100 * time.Millisecond,
+		maxBackoff: 5 * time.Second,
+		retryable: func(error) bool {
+			return true
+		},
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	if optsDef.attempts < 1 {
+		optsDef.attempts = 1
+	}
+
+	return &MapRetryStream[T, V]{
+		inner:  inner,
+		mapper: mapper,
+		opts:   optsDef,
+	}
+}
+
+func (s *MapRetryStream[T, V]) Next() bool {
+	if s.done {
+		return false
+	}
+
+	if err := s.opts.ctx.Err(); err != nil {
+		return s.end(err)
+	}
+
+	for s.inner.Next() {
+		item := s.inner.Data()
+
+		value, attempts, err := s.attempt(item)
+		if err == nil {
+			s.current = value
+			return true
+		}
+
+		if ctxErr := s.opts.ctx.Err(); ctxErr != nil {
+			return s.end(ctxErr)
+		}
+
+		if s.opts.deadLetter == nil {
+			return s.end(err)
+		}
+
+		failed := Failed[T]{Item: item, Err: err, Attempts: attempts}
+		if _, dlErr := s.opts.deadLetter.Write(failed); dlErr != nil {
+			return s.end(fmt.Errorf("dead letter: %w", dlErr))
+		}
+	}
+
+	return s.end(nil)
+}
+
+// end stops the stream with err, flushing the dead-letter stream so the items
+// routed to it are not left in its buffers.
+func (s *MapRetryStream[T, V]) end(err error) bool {
+	s.done = true
+	s.err = err
+
+	if s.opts.deadLetter != nil {
+		if flushErr := s.opts.deadLetter.Flush(); flushErr != nil && s.err == nil {
+			s.err = fmt.Errorf("dead letter: %w", flushErr)
+		}
+	}
+
+	return false
+}
+
+// attempt calls the mapper until it succeeds, fails with a non-retryable
+// error or runs out of attempts.
+func (s *MapRetryStream[T, V]) attempt(item T) (V, int, error) {
+	backoff := s.opts.backoff
+
+	for attempt := 1; ; attempt++ {
+		value, err := s.mapper(item)
+		if err == nil || attempt >= s.opts.attempts || !s.opts.retryable(err) {
+			return value, attempt, err
+		}
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-timer.C:
+		case <-s.opts.ctx.Done():
+			timer.Stop()
+			return value, attempt, err
+		}
+
+		backoff *= 2
+		if s.opts.maxBackoff > 0 && backoff > s.opts.maxBackoff {
+			backoff = s.opts.maxBackoff
+		}
+	}
+}
+
+func (s *MapRetryStream[T, V]) Data() V {
+	return s.current
+}
+
+func (s *MapRetryStream[T, V]) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.inner.Err()
+}
+
+// Close flushes the dead-letter stream, if the stream did not run to its end,
+// and closes the inner stream.
+func (s *MapRetryStream[T, V]) Close() error {
+	var err error
+	if !s.done {
+		s.end(nil)
+		err = s.err
+	}
+
+	return errors.Join(err, s.inner.Close())
+}
+
+func (s *MapRetryStream[T, V]) Iter() iter.Seq[V] {
+	return Iter(s)
+}
+
+func (s *MapRetryStream[T, V]) Iter2() iter.Seq2[V, error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[any] = new(MapRetryStream[any, any])
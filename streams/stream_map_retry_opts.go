@@ -0,0 +1,67 @@
+package streams
+
+import (
+	"context"
+	"time"
+)
+
+type MapRetryOpt[T any] func(*mapRetryOpts[T])
+
+type mapRetryOpts[T any] struct {
+	ctx        context.Context
+	attempts   int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	retryable  func(error) bool
+	deadLetter WriteStream[Failed[T]]
+}
+
+func (fn MapRetryOpt[T]) apply(o *mapRetryOpts[T]) {
+	fn(o)
+}
+
+// WithMapRetryContext aborts a pending backoff once ctx is done, reporting
+// ctx.Err() through Err.
+func WithMapRetryContext[T any](ctx context.Context) MapRetryOpt[T] {
+	return func(o *mapRetryOpts[T]) {
+		o.ctx = ctx
+	}
+}
+
+// WithMapRetryAttempts sets how many times the mapper is called for an item,
+// including the first call. Defaults to 3.
+func WithMapRetryAttempts[T any](attempts int) MapRetryOpt[T] {
+	return func(o *mapRetryOpts[T]) {
+		o.attempts = attempts
+	}
+}
+
+// WithMapRetryBackoff sets the wait before the first retry, doubled after
+// every failed attempt up to max. A zero max leaves the backoff unbounded.
+// Defaults to 100ms, up to 5s.
+func WithMapRetryBackoff[T any](initial, max time.Duration) MapRetryOpt[T] {
+	return func(o *mapRetryOpts[T]) {
+		o.backoff = initial
+		o.maxBackoff = max
+	}
+}
+
+// WithMapRetryClassifier sets which errors are worth retrying. Items failing
+// with any other error are not retried. Defaults to retrying every error.
+func WithMapRetryClassifier[T any](retryable func(error) bool) MapRetryOpt[T] {
+	return func(o *mapRetryOpts[T]) {
+		o.retryable = retryable
+	}
+}
+
+// WithMapRetryDeadLetter routes items whose mapping ultimately failed to w,
+// together with their error, and carries on with the next item instead of
+// stopping the stream. An error writing to w stops the stream.
+//
+// w is flushed once the stream ends or is closed, but never closed: the caller owns it and
+// closes it once done, which allows sharing it between several streams.
+func WithMapRetryDeadLetter[T any](w WriteStream[Failed[T]]) MapRetryOpt[T] {
+	return func(o *mapRetryOpts[T]) {
+		o.deadLetter = w
+	}
+}
@@ -0,0 +1,217 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTransient = errors.New("transient")
+
+// flakyMapper fails the first failures[item] calls for every item with
+// errTransient, and every call for negative items with a permanent error.
+func flakyMapper(failures map[int]int) (func(int) (string, error), map[int]int) {
+	calls := make(map[int]int)
+
+	return func(x int) (string, error) {
+		calls[x]++
+
+		if x < 0 {
+			return "", fmt.Errorf("invalid item %d", x)
+		}
+
+		if calls[x] <= failures[x] {
+			return "", errTransient
+		}
+
+		return strconv.Itoa(x), nil
+	}, calls
+}
+
+func TestMapRetryStream(t *testing.T) {
+	fast := WithMapRetryBackoff[int](time.Microsecond, time.Millisecond)
+
+	t.Run("Recovers from transient errors", func(t *testing.T) {
+		mapper, calls := flakyMapper(map[int]int{2: 2})
+
+		result, err := Consume(MapRetry(MemReader([]int{1, 2, 3}, nil), mapper, fast))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "2", "3"}, result)
+		assert.Equal(t, 3, calls[2])
+	})
+
+	t.Run("Stops once attempts are exhausted", func(t *testing.T) {
+		mapper, calls := flakyMapper(map[int]int{2: 5})
+
+		s := MapRetry(MemReader([]int{1, 2, 3}, nil), mapper, fast, WithMapRetryAttempts[int](2))
+
+		var got []string
+		for s.Next() {
+			got = append(got, s.Data())
+		}
+
+		assert.Equal(t, []string{"1"}, got)
+		assert.ErrorIs(t, s.Err(), errTransient)
+		assert.Equal(t, 2, calls[2])
+		assert.Zero(t, calls[3])
+	})
+
+	t.Run("Classifier skips permanent errors", func(t *testing.T) {
+		mapper, calls := flakyMapper(nil)
+
+		s := MapRetry(MemReader([]int{-1}, nil), mapper, fast,
+			WithMapRetryClassifier[int](func(err error) bool {
+				return errors.Is(err, errTransient)
+			}),
+		)
+
+		assert.False(t, s.Next())
+		assert.ErrorContains(t, s.Err(), "invalid item -1")
+		assert.Equal(t, 1, calls[-1])
+	})
+
+	t.Run("Dead letter keeps the stream going", func(t *testing.T) {
+		mapper, _ := flakyMapper(map[int]int{2: 5})
+		dead := MemWriter[Failed[int]]()
+
+		s := MapRetry(MemReader([]int{1, 2, -3, 4}, nil), mapper, fast,
+			WithMapRetryAttempts[int](3),
+			WithMapRetryDeadLetter(dead),
+		)
+
+		result, err := Consume(s)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "4"}, result)
+
+		failed := dead.Items()
+		require.Len(t, failed, 2)
+
+		assert.Equal(t, 2, failed[0].Item)
+		assert.ErrorIs(t, failed[0].Err, errTransient)
+		assert.Equal(t, 3, failed[0].Attempts)
+
+		assert.Equal(t, -3, failed[1].Item)
+		assert.ErrorContains(t, failed[1].Err, "invalid item -3")
+	})
+
+	t.Run("Dead letter failure stops the stream", func(t *testing.T) {
+		mapper, _ := flakyMapper(nil)
+		dead := MemWriter[Failed[int]]()
+		dead.SetError(errors.New("queue full"))
+
+		s := MapRetry(MemReader([]int{-1, 2}, nil), mapper, fast, WithMapRetryDeadLetter(dead))
+
+		assert.False(t, s.Next())
+		assert.ErrorContains(t, s.Err(), "dead letter: queue full")
+	})
+
+	t.Run("Dead letter is flushed at the end", func(t *testing.T) {
+		mapper, _ := flakyMapper(nil)
+		dead := &flushCounter[Failed[int]]{MemoryWriteStream: MemWriter[Failed[int]]()}
+
+		s := MapRetry(MemReader([]int{-1, 2}, nil), mapper, fast, WithMapRetryDeadLetter(dead))
+
+		require.True(t, s.Next())
+		assert.Zero(t, dead.flushes)
+
+		assert.False(t, s.Next())
+		assert.False(t, s.Next())
+		assert.NoError(t, s.Err())
+		assert.Equal(t, 1, dead.flushes)
+		assert.Len(t, dead.Items(), 1)
+
+		dead = &flushCounter[Failed[int]]{
+			MemoryWriteStream: MemWriter[Failed[int]](),
+			flushErr:          errors.New("disk full"),
+		}
+
+		s = MapRetry(MemReader([]int{-1}, nil), mapper, fast, WithMapRetryDeadLetter(dead))
+
+		assert.False(t, s.Next())
+		assert.ErrorContains(t, s.Err(), "dead letter: disk full")
+	})
+
+	t.Run("Dead letter is flushed on Close", func(t *testing.T) {
+		mapper, _ := flakyMapper(nil)
+		dead := &flushCounter[Failed[int]]{MemoryWriteStream: MemWriter[Failed[int]]()}
+
+		s := MapRetry(MemReader([]int{-1, 2, -3}, nil), mapper, fast, WithMapRetryDeadLetter(dead))
+
+		require.True(t, s.Next())
+		require.NoError(t, s.Close())
+		assert.Equal(t, 1, dead.flushes)
+		assert.Len(t, dead.Items(), 1)
+		assert.False(t, s.Next())
+
+		// Not flushed again once the stream ran to its end
+		require.NoError(t, s.Close())
+		assert.Equal(t, 1, dead.flushes)
+
+		dead = &flushCounter[Failed[int]]{
+			MemoryWriteStream: MemWriter[Failed[int]](),
+			flushErr:          errors.New("disk full"),
+		}
+
+		s = MapRetry(MemReader([]int{-1, 2}, nil), mapper, fast, WithMapRetryDeadLetter(dead))
+
+		require.True(t, s.Next())
+		assert.ErrorContains(t, s.Close(), "dead letter: disk full")
+	})
+
+	t.Run("Context aborts the backoff", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		mapper, _ := flakyMapper(map[int]int{1: 100})
+		dead := MemWriter[Failed[int]]()
+
+		s := MapRetry(MemReader([]int{1}, nil), mapper,
+			WithMapRetryBackoff[int](time.Hour, 0),
+			WithMapRetryContext[int](ctx),
+			WithMapRetryDeadLetter(dead),
+		)
+
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), context.DeadlineExceeded)
+		assert.Empty(t, dead.Items(), "cancelled items are not dead letters")
+	})
+}
+
+// ExampleMapRetry demonstrates retrying a flaky call and routing failures to a dead-letter stream.
+func ExampleMapRetry() {
+	calls := 0
+	enrich := func(id int) (string, error) {
+		calls++
+		if id == 2 && calls < 3 {
+			return "", errors.New("timeout")
+		}
+		if id == 3 {
+			return "", errors.New("not found")
+		}
+		return fmt.Sprintf("user-%d", id), nil
+	}
+
+	deadLetters := MemWriter[Failed[int]]()
+
+	stream := MapRetry(MemReader([]int{1, 2, 3, 4}, nil), enrich,
+		WithMapRetryBackoff[int](time.Millisecond, 10*time.Millisecond),
+		WithMapRetryClassifier[int](func(err error) bool { return err.Error() == "timeout" }),
+		WithMapRetryDeadLetter(deadLetters),
+	)
+
+	users, _ := Consume(stream)
+	fmt.Println(users)
+
+	for _, failed := range deadLetters.Items() {
+		fmt.Printf("failed %d after %d attempt(s): %v\n", failed.Item, failed.Attempts, failed.Err)
+	}
+	// Output:
+	// [user-1 user-2 user-4]
+	// failed 3 after 1 attempt(s): not found
+}
@@ -0,0 +1,161 @@
+package streams
+
+import (
+	"container/heap"
+	"errors"
+	"io"
+	"iter"
+)
+
+type (
+	mergeItem[T any] struct {
+		value  T
+		source int
+	}
+
+	mergeHeap[T any] struct {
+		items []mergeItem[T]
+		less  func(a, b T) bool
+	}
+
+	// MergeSortedStream performs a k-way merge of streams sorted by the same
+	// order, holding a single item per stream in memory.
+	MergeSortedStream[T any] struct {
+		streams []ReadStream[T]
+		queue   *mergeHeap[T]
+		last    int
+		current T
+		err     error
+		started bool
+		done    bool
+	}
+)
+
+// MergeSorted creates a new ReadStream that merges streams already sorted
+// according to less into a single sorted stream, e.g. exports of several
+// shards sorted by the same key. Items comparing equal are yielded in the
+// order of the streams they come from.
+//
+// The first inner error other than io.EOF stops the stream and is reported by
+// Err. Close closes all the inner streams.
+func MergeSorted[T any](less func(a, b T) bool, streams ...ReadStream[T]) ReadStream[T] {
+	return &MergeSortedStream[T]{
+		streams: streams,
+		queue: &mergeHeap[T]{
+			items: make([]mergeItem[T], 0, len(streams)),
+			less:  less,
+		},
+		last: -1,
+	}
+}
+
+func (s *MergeSortedStream[T]) Next() bool {
+	if s.done {
+		return false
+	}
+
+	if !s.started {
+		s.started = true
+
+		for i := range s.streams {
+			if err := s.advance(i); err != nil {
+				return s.fail(err)
+			}
+		}
+	} else if s.last >= 0 {
+		if err := s.advance(s.last); err != nil {
+			return s.fail(err)
+		}
+	}
+
+	if s.queue.Len() == 0 {
+		s.done = true
+		return false
+	}
+
+	item := heap.Pop(s.queue).(mergeItem[T])
+	s.current = item.value
+	s.last = item.source
+	return true
+}
+
+// advance pushes the next item of the given stream into the queue. io.EOF
+// counts as a clean end of the stream.
+func (s *MergeSortedStream[T]) advance(source int) error {
+	stream := s.streams[source]
+
+	if !stream.Next() {
+		if err := stream.Err(); !errors.Is(err, io.EOF) {
+			return err
+		}
+		return nil
+	}
+
+	heap.Push(s.queue, mergeItem[T]{value: stream.Data(), source: source})
+	return nil
+}
+
+func (s *MergeSortedStream[T]) fail(err error) bool {
+	s.err = err
+	s.done = true
+	return false
+}
+
+func (s *MergeSortedStream[T]) Data() T {
+	return s.current
+}
+
+func (s *MergeSortedStream[T]) Err() error {
+	return s.err
+}
+
+func (s *MergeSortedStream[T]) Close() error {
+	errs := make([]error, len(s.streams))
+	for i, stream := range s.streams {
+		errs[i] = stream.Close()
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *MergeSortedStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *MergeSortedStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+func (h *mergeHeap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *mergeHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+
+	if h.less(a.value, b.value) {
+		return true
+	}
+	if h.less(b.value, a.value) {
+		return false
+	}
+	return a.source < b.source
+}
+
+func (h *mergeHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *mergeHeap[T]) Push(x any) {
+	h.items = append(h.items, x.(mergeItem[T]))
+}
+
+func (h *mergeHeap[T]) Pop() any {
+	n := len(h.items)
+	x := h.items[n-1]
+	h.items[n-1] = mergeItem[T]{}
+	h.items = h.items[:n-1]
+	return x
+}
+
+var _ ReadStream[any] = new(MergeSortedStream[any])
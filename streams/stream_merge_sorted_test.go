@@ -0,0 +1,129 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeSortedStream(t *testing.T) {
+	errRead := errors.New("read failed")
+
+	type row struct {
+		key    int
+		source string
+	}
+
+	less := func(a, b row) bool {
+		return a.key < b.key
+	}
+
+	tests := []struct {
+		name        string
+		streams     []ReadStream[row]
+		expected    []row
+		expectedErr error
+	}{
+		{
+			name:     "No streams",
+			expected: nil,
+		},
+		{
+			name: "Single stream",
+			streams: []ReadStream[row]{
+				MemReader([]row{{1, "a"}, {2, "a"}}, nil),
+			},
+			expected: []row{{1, "a"}, {2, "a"}},
+		},
+		{
+			name: "Three streams with ties",
+			streams: []ReadStream[row]{
+				MemReader([]row{{1, "a"}, {4, "a"}, {7, "a"}}, nil),
+				MemReader([]row{{2, "b"}, {4, "b"}}, nil),
+				MemReader([]row{}, nil),
+				MemReader([]row{{0, "d"}, {4, "d"}, {9, "d"}}, nil),
+			},
+			expected: []row{
+				{0, "d"}, {1, "a"}, {2, "b"},
+				{4, "a"}, {4, "b"}, {4, "d"},
+				{7, "a"}, {9, "d"},
+			},
+		},
+		{
+			name: "Error stops the stream",
+			streams: []ReadStream[row]{
+				MemReader([]row{{1, "a"}, {5, "a"}}, nil),
+				MemReader([]row{{2, "b"}}, errRead),
+			},
+			expected:    []row{{1, "a"}, {2, "b"}},
+			expectedErr: errRead,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := MergeSorted(less, tc.streams...)
+
+			var got []row
+			for s.Next() {
+				got = append(got, s.Data())
+			}
+
+			assert.Equal(t, tc.expected, got)
+			assert.ErrorIs(t, s.Err(), tc.expectedErr)
+			assert.False(t, s.Next())
+		})
+	}
+}
+
+func TestMergeSortedStream_JSON(t *testing.T) {
+	s := MergeSorted(func(a, b int) bool { return a < b },
+		jsonRows[int]("1\n4\n"),
+		jsonRows[int]("2\n3\n5\n"),
+	)
+
+	result, err := Consume(s)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, result)
+	assert.NoError(t, s.Err())
+}
+
+func TestMergeSortedStream_Close(t *testing.T) {
+	a := track[int](MemReader([]int{1}, nil))
+	b := track[int](MemReader([]int{2}, nil))
+
+	s := MergeSorted(func(a, b int) bool { return a < b }, ReadStream[int](a), b)
+
+	assert.NoError(t, s.Close())
+	assert.True(t, a.closed)
+	assert.True(t, b.closed)
+}
+
+// ExampleMergeSorted demonstrates merging exports that are already sorted by timestamp.
+func ExampleMergeSorted() {
+	type event struct {
+		ts   int
+		name string
+	}
+
+	shard1 := MemReader([]event{{1, "login"}, {5, "logout"}}, nil)
+	shard2 := MemReader([]event{{2, "view"}, {3, "click"}, {8, "view"}}, nil)
+
+	stream := MergeSorted(func(a, b event) bool {
+		return a.ts < b.ts
+	}, ReadStream[event](shard1), shard2)
+	defer stream.Close()
+
+	for stream.Next() {
+		e := stream.Data()
+		fmt.Println(e.ts, e.name)
+	}
+	// Output:
+	// 1 login
+	// 2 view
+	// 3 click
+	// 5 logout
+	// 8 view
+}
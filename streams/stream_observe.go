@@ -0,0 +1,187 @@
+package streams
+
+import (
+	"errors"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/sonirico/vago/clock"
+)
+
+type (
+	// Observer receives the metrics of streams wrapped with Observe or
+	// ObserveWriter. Implementations must be safe for concurrent use, since
+	// a single observer is usually shared by every stage of a pipeline.
+	Observer interface {
+		// ObserveItem is called for every item read or written by the stream
+		// called name, with the bytes written, zero for reads, and the time
+		// spent in the inner Next or Write call.
+		ObserveItem(name string, bytes int64, latency time.Duration)
+		// ObserveError is called for every error returned by the stream.
+		ObserveError(name string, err error)
+	}
+
+	// ObserveStream reports the items and errors of the inner stream to an
+	// Observer.
+	ObserveStream[T any] struct {
+		inner    ReadStream[T]
+		name     string
+		observer Observer
+		clock    clock.Clock
+		reported bool
+	}
+
+	// ObserveWriteStream reports the writes and errors of the inner stream to
+	// an Observer.
+	ObserveWriteStream[T any] struct {
+		inner    WriteStream[T]
+		name     string
+		observer Observer
+		clock    clock.Clock
+	}
+
+	multiObserver []Observer
+)
+
+func newObserveOpts(opts []ObserveOpt) observeOpts {
+	optsDef := observeOpts{
+		clock: clock.New(),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	return optsDef
+}
+
+// Observe wraps a ReadStream so that every item it yields, and the error it
+// ends with, if any, are reported to observer under name. The latency of an
+// item is the time spent by the inner stream producing it.
+func Observe[T any](inner ReadStream[T], name string, observer Observer, opts ...ObserveOpt) ReadStream[T] {
+	optsDef := newObserveOpts(opts)
+
+	return &ObserveStream[T]{
+		inner:    inner,
+		name:     name,
+		observer: observer,
+		clock:    optsDef.clock,
+	}
+}
+
+func (s *ObserveStream[T]) Next() bool {
+	start := s.clock.Now()
+
+	if s.inner.Next() {
+		s.observer.ObserveItem(s.name, 0, s.clock.Now().Sub(start))
+		return true
+	}
+
+	if err := s.inner.Err(); err != nil && !errors.Is(err, io.EOF) && !s.reported {
+		s.reported = true
+		s.observer.ObserveError(s.name, err)
+	}
+
+	return false
+}
+
+func (s *ObserveStream[T]) Data() T {
+	return s.inner.Data()
+}
+
+func (s *ObserveStream[T]) Err() error {
+	return s.inner.Err()
+}
+
+func (s *ObserveStream[T]) Close() error {
+	return s.observe(s.inner.Close())
+}
+
+func (s *ObserveStream[T]) observe(err error) error {
+	if err != nil {
+		s.observer.ObserveError(s.name, err)
+	}
+	return err
+}
+
+func (s *ObserveStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *ObserveStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+// ObserveWriter wraps a WriteStream so that every write, with the bytes it
+// reports, and every write, flush or close error are reported to observer
+// under name.
+func ObserveWriter[T any](inner WriteStream[T], name string, observer Observer, opts ...ObserveOpt) WriteStream[T] {
+	optsDef := newObserveOpts(opts)
+
+	return &ObserveWriteStream[T]{
+		inner:    inner,
+		name:     name,
+		observer: observer,
+		clock:    optsDef.clock,
+	}
+}
+
+// Write writes item to the inner stream and reports it
+func (w *ObserveWriteStream[T]) Write(item T) (int64, error) {
+	start := w.clock.Now()
+
+	n, err := w.inner.Write(item)
+	if err != nil {
+		w.observer.ObserveError(w.name, err)
+		return n, err
+	}
+
+	w.observer.ObserveItem(w.name, n, w.clock.Now().Sub(start))
+	return n, nil
+}
+
+// Flush flushes the inner stream
+func (w *ObserveWriteStream[T]) Flush() error {
+	return w.observe(w.inner.Flush())
+}
+
+// Err returns the error state of the inner stream
+func (w *ObserveWriteStream[T]) Err() error {
+	return w.inner.Err()
+}
+
+// Close closes the inner stream
+func (w *ObserveWriteStream[T]) Close() error {
+	return w.observe(w.inner.Close())
+}
+
+func (w *ObserveWriteStream[T]) observe(err error) error {
+	if err != nil {
+		w.observer.ObserveError(w.name, err)
+	}
+	return err
+}
+
+// Observers combines several observers into one, e.g. to export metrics and
+// log progress at the same time.
+func Observers(observers ...Observer) Observer {
+	return multiObserver(observers)
+}
+
+func (m multiObserver) ObserveItem(name string, bytes int64, latency time.Duration) {
+	for _, o := range m {
+		o.ObserveItem(name, bytes, latency)
+	}
+}
+
+func (m multiObserver) ObserveError(name string, err error) {
+	for _, o := range m {
+		o.ObserveError(name, err)
+	}
+}
+
+var (
+	_ ReadStream[any]  = new(ObserveStream[any])
+	_ WriteStream[any] = new(ObserveWriteStream[any])
+)
@@ -0,0 +1,47 @@
+package streams
+
+import "github.com/sonirico/vago/clock"
+
+type ObserveOpt func(*observeOpts)
+
+type observeOpts struct {
+	clock clock.Clock
+}
+
+func (fn ObserveOpt) apply(o *observeOpts) {
+	fn(o)
+}
+
+// WithObserveClock sets the clock used to measure latencies.
+func WithObserveClock(c clock.Clock) ObserveOpt {
+	return func(o *observeOpts) {
+		o.clock = c
+	}
+}
+
+type PrometheusOpt func(*prometheusOpts)
+
+type prometheusOpts struct {
+	namespace string
+	buckets   []float64
+}
+
+func (fn PrometheusOpt) apply(o *prometheusOpts) {
+	fn(o)
+}
+
+// WithPrometheusNamespace prefixes every metric name with namespace and an
+// underscore.
+func WithPrometheusNamespace(namespace string) PrometheusOpt {
+	return func(o *prometheusOpts) {
+		o.namespace = namespace
+	}
+}
+
+// WithPrometheusBuckets sets the upper bounds, in seconds, of the latency
+// histogram. Defaults to DefaultLatencyBuckets.
+func WithPrometheusBuckets(buckets []float64) PrometheusOpt {
+	return func(o *prometheusOpts) {
+		o.buckets = buckets
+	}
+}
@@ -0,0 +1,253 @@
+package streams
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sonirico/vago/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type observedItem struct {
+	name    string
+	bytes   int64
+	latency time.Duration
+}
+
+type recordingObserver struct {
+	mu     sync.Mutex
+	items  []observedItem
+	errors []error
+}
+
+func (r *recordingObserver) ObserveItem(name string, bytes int64, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.items = append(r.items, observedItem{name, bytes, latency})
+}
+
+func (r *recordingObserver) ObserveError(_ string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, err)
+}
+
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *recordingLogger) Infof(format string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Lines() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string(nil), l.lines...)
+}
+
+func TestObserveStream(t *testing.T) {
+	clk := &steppingClock{step: time.Millisecond}
+
+	t.Run("Reports items with their latency", func(t *testing.T) {
+		obs := new(recordingObserver)
+
+		result, err := Consume(Observe(MemReader([]int{1, 2, 3}, nil), "numbers", obs, WithObserveClock(clk)))
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 2, 3}, result)
+
+		require.Len(t, obs.items, 3)
+		for _, item := range obs.items {
+			assert.Equal(t, observedItem{"numbers", 0, time.Millisecond}, item)
+		}
+		assert.Empty(t, obs.errors)
+	})
+
+	t.Run("Reports the final error once", func(t *testing.T) {
+		obs := new(recordingObserver)
+		boom := errors.New("boom")
+
+		s := Observe(MemReader([]int(nil), boom), "failing", obs)
+		assert.False(t, s.Next())
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), boom)
+
+		assert.Equal(t, []error{boom}, obs.errors)
+	})
+
+	t.Run("Reports close errors", func(t *testing.T) {
+		obs := new(recordingObserver)
+		inner := track(MemReader([]int{1}, nil))
+		inner.closeErr = errors.New("close failed")
+
+		s := Observe[int](inner, "tracked", obs)
+		assert.EqualError(t, s.Close(), "close failed")
+		assert.Equal(t, []error{inner.closeErr}, obs.errors)
+	})
+}
+
+func TestObserveWriteStream(t *testing.T) {
+	clk := &steppingClock{step: time.Millisecond}
+
+	t.Run("Reports written bytes", func(t *testing.T) {
+		obs := new(recordingObserver)
+
+		w := ObserveWriter[int](MemWriter[int](), "mem", obs, WithObserveClock(clk))
+		_, err := w.Write(1)
+		require.NoError(t, err)
+		require.NoError(t, w.Flush())
+
+		assert.Equal(t, []observedItem{{"mem", 1, time.Millisecond}}, obs.items)
+	})
+
+	t.Run("Reports write errors", func(t *testing.T) {
+		obs := new(recordingObserver)
+		inner := MemWriter[int]()
+		inner.SetError(errors.New("full"))
+
+		w := ObserveWriter[int](inner, "mem", obs)
+		_, err := w.Write(1)
+		assert.EqualError(t, err, "full")
+
+		assert.Empty(t, obs.items)
+		assert.Equal(t, []error{err}, obs.errors)
+	})
+}
+
+func TestObservers(t *testing.T) {
+	a, b := new(recordingObserver), new(recordingObserver)
+
+	_, err := Consume(Observe(MemReader([]int{1, 2}, nil), "numbers", Observers(a, b)))
+	require.NoError(t, err)
+
+	assert.Len(t, a.items, 2)
+	assert.Len(t, b.items, 2)
+}
+
+func TestPrometheusObserver(t *testing.T) {
+	p := NewPrometheusObserver(
+		WithPrometheusNamespace("etl"),
+		WithPrometheusBuckets([]float64{0.01, 0.001}),
+	)
+
+	p.ObserveItem("orders", 10, 500*time.Microsecond)
+	p.ObserveItem("orders", 20, 5*time.Millisecond)
+	p.ObserveItem(`we"ird`, 0, time.Second)
+	p.ObserveError("orders", errors.New("boom"))
+
+	var buf bytes.Buffer
+	n, err := p.WriteTo(&buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	expected := `# HELP etl_stream_items_total Items read or written by the stream.
+# TYPE etl_stream_items_total counter
+etl_stream_items_total{stream="orders"} 2
+etl_stream_items_total{stream="we\"ird"} 1
+# HELP etl_stream_bytes_total Bytes written by the stream.
+# TYPE etl_stream_bytes_total counter
+etl_stream_bytes_total{stream="orders"} 30
+etl_stream_bytes_total{stream="we\"ird"} 0
+# HELP etl_stream_errors_total Errors returned by the stream.
+# TYPE etl_stream_errors_total counter
+etl_stream_errors_total{stream="orders"} 1
+etl_stream_errors_total{stream="we\"ird"} 0
+# HELP etl_stream_item_latency_seconds Time spent reading or writing an item.
+# TYPE etl_stream_item_latency_seconds histogram
+etl_stream_item_latency_seconds_bucket{stream="orders",le="0.001"} 1
+etl_stream_item_latency_seconds_bucket{stream="orders",le="0.01"} 2
+etl_stream_item_latency_seconds_bucket{stream="orders",le="+Inf"} 2
+etl_stream_item_latency_seconds_sum{stream="orders"} 0.0055
+etl_stream_item_latency_seconds_count{stream="orders"} 2
+etl_stream_item_latency_seconds_bucket{stream="we\"ird",le="0.001"} 0
+etl_stream_item_latency_seconds_bucket{stream="we\"ird",le="0.01"} 0
+etl_stream_item_latency_seconds_bucket{stream="we\"ird",le="+Inf"} 1
+etl_stream_item_latency_seconds_sum{stream="we\"ird"} 1
+etl_stream_item_latency_seconds_count{stream="we\"ird"} 1
+`
+	assert.Equal(t, expected, buf.String())
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, expected, rec.Body.String())
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain"))
+}
+
+func TestProgressObserver(t *testing.T) {
+	t.Run("Report logs every stream", func(t *testing.T) {
+		clk := clock.NewMock(time.Unix(0, 0))
+		logger := new(recordingLogger)
+
+		p := NewProgressObserver(logger, 0, WithObserveClock(clk))
+		p.ObserveItem("b", 0, 0)
+		p.ObserveItem("a", 8, 0)
+		p.ObserveItem("a", 8, 0)
+		p.ObserveError("a", errors.New("boom"))
+
+		clk.Add(2 * time.Second)
+		p.Report()
+
+		assert.Equal(t, []string{
+			"stream a: 2 items, 16 bytes, 1 errors in 2s (1.0 items/s)",
+			"stream b: 1 items, 0 bytes, 0 errors in 2s (0.5 items/s)",
+		}, logger.Lines())
+	})
+
+	t.Run("Logs periodically until stopped", func(t *testing.T) {
+		logger := new(recordingLogger)
+
+		p := NewProgressObserver(logger, time.Millisecond)
+		p.ObserveItem("a", 0, 0)
+
+		assert.Eventually(t, func() bool {
+			return len(logger.Lines()) >= 2
+		}, time.Second, time.Millisecond)
+
+		p.Stop()
+		p.Stop()
+
+		lines := len(logger.Lines())
+		time.Sleep(5 * time.Millisecond)
+		assert.Equal(t, lines, len(logger.Lines()), "no reports after Stop")
+	})
+}
+
+type stdoutLogger struct{}
+
+func (stdoutLogger) Infof(format string, args ...any) {
+	fmt.Printf(format+"\n", args...)
+}
+
+// ExampleObserve demonstrates collecting pipeline metrics and logging progress.
+func ExampleObserve() {
+	clk := clock.NewMock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	metrics := NewPrometheusObserver()
+	progress := NewProgressObserver(stdoutLogger{}, time.Minute, WithObserveClock(clk))
+
+	observer := Observers(metrics, progress)
+
+	src := Observe(MemReader([]string{"a", "b", "c"}, nil), "source", observer)
+	dst := ObserveWriter(MemWriter[string](), "sink", observer)
+
+	if _, err := Pipe(src, dst); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	// Stop logs a final report
+	clk.Add(time.Second)
+	progress.Stop()
+	// Output:
+	// stream sink: 3 items, 3 bytes, 0 errors in 1s (3.0 items/s)
+	// stream source: 3 items, 0 bytes, 0 errors in 1s (3.0 items/s)
+}
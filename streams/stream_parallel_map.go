@@ -0,0 +1,275 @@
+package streams
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+type (
+	parallelJob[T any] struct {
+		seq  uint64
+		item T
+	}
+
+	parallelResult[V any] struct {
+		seq   uint64
+		value V
+		err   error
+	}
+
+	// ParallelMapStream applies a mapper function to the items of the inner
+	// stream using a pool of goroutines.
+	//
+	// A single feeder goroutine is the only one touching the inner stream, so
+	// inner streams do not need to be safe for concurrent use.
+	ParallelMapStream[T, V any] struct {
+		inner   ReadStream[T]
+		workers int
+		mapper  func(context.Context, T) (V, error)
+		opts    parallelMapOpts
+
+		ctx     context.Context
+		cancel  context.CancelFunc
+		jobs    chan parallelJob[T]
+		results chan parallelResult[V]
+		slots   chan struct{}
+		wg      sync.WaitGroup
+
+		// innerErr is written by the feeder before it exits and only read
+		// once results has been closed.
+		innerErr error
+
+		pending map[uint64]V
+		next    uint64
+		current V
+		err     error
+		started bool
+		done    bool
+		closed  bool
+	}
+)
+
+// ParallelMap creates a new ReadStream that transforms elements from the inner
+// stream using `workers` goroutines. Results are yielded in the same order as
+// the source unless WithParallelMapUnordered is given.
+//
+// The first mapper error cancels the context handed to in-flight calls, stops
+// the stream and is reported by Err(). Close cancels pending work and waits
+// for every goroutine to exit before closing the inner stream. A pending read
+// on inner streams able to abort it, such as the ones returned by Channel, is
+// interrupted; any other stream is waited for until its Next returns.
+func ParallelMap[T, V any](
+	inner ReadStream[T],
+	workers int,
+	mapper func(context.Context, T) (V, error),
+	opts ...ParallelMapOpt,
+) ReadStream[V] {
+	if workers < 1 {
+		workers = 1
+	}
+
+	optsDef := parallelMapOpts{
+		ctx:    context.Background(),
+		buffer: workers,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	if optsDef.buffer < 0 {
+		optsDef.buffer = 0
+	}
+
+	return &ParallelMapStream[T, V]{
+		inner:   inner,
+		workers: workers,
+		mapper:  mapper,
+		opts:    optsDef,
+	}
+}
+
+func (s *ParallelMapStream[T, V]) start() {
+	s.started = true
+	s.ctx, s.cancel = context.WithCancel(s.opts.ctx)
+	s.jobs = make(chan parallelJob[T])
+	s.results = make(chan parallelResult[V], s.workers)
+	s.slots = make(chan struct{}, s.workers+s.opts.buffer)
+	s.pending = make(map[uint64]V)
+
+	// Streams able to abort a blocking read are bound to the context of the
+	// pool, so cancelling it on Close or on a mapper error also releases a
+	// feeder waiting for an idle source.
+	if aware, ok := s.inner.(contextAware); ok {
+		aware.setContext(s.ctx)
+	}
+
+	s.wg.Add(1 + s.workers)
+
+	go s.feed()
+
+	for i := 0; i < s.workers; i++ {
+		go s.work()
+	}
+
+	go func() {
+		s.wg.Wait()
+		close(s.results)
+	}()
+}
+
+// feed reads the inner stream and dispatches jobs. A slot must be acquired
+// before each item is read, which bounds the number of results waiting to be
+// reordered.
+func (s *ParallelMapStream[T, V]) feed() {
+	defer s.wg.Done()
+	defer close(s.jobs)
+
+	var seq uint64
+
+	for {
+		select {
+		case s.slots <- struct{}{}:
+		case <-s.ctx.Done():
+			return
+		}
+
+		if !s.inner.Next() {
+			// A read aborted by the stream itself being stopped is not an
+			// error of the inner stream
+			if s.ctx.Err() == nil || s.opts.ctx.Err() != nil {
+				s.innerErr = s.inner.Err()
+			}
+			return
+		}
+
+		select {
+		case s.jobs <- parallelJob[T]{seq: seq, item: s.inner.Data()}:
+			seq++
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *ParallelMapStream[T, V]) work() {
+	defer s.wg.Done()
+
+	for job := range s.jobs {
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		value, err := s.mapper(s.ctx, job.item)
+
+		select {
+		case s.results <- parallelResult[V]{seq: job.seq, value: value, err: err}:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *ParallelMapStream[T, V]) Next() bool {
+	if s.done {
+		return false
+	}
+
+	if !s.started {
+		s.start()
+	}
+
+	for {
+		if !s.opts.unordered {
+			if value, ok := s.pending[s.next]; ok {
+				delete(s.pending, s.next)
+				s.next++
+				s.yield(value)
+				return true
+			}
+		}
+
+		res, ok := <-s.results
+		if !ok {
+			s.finish()
+			return false
+		}
+
+		if res.err != nil {
+			s.err = res.err
+			s.finish()
+			return false
+		}
+
+		if s.opts.unordered {
+			s.yield(res.value)
+			return true
+		}
+
+		s.pending[res.seq] = res.value
+	}
+}
+
+func (s *ParallelMapStream[T, V]) yield(value V) {
+	<-s.slots
+	s.current = value
+}
+
+// finish stops all goroutines and settles the final error of the stream.
+func (s *ParallelMapStream[T, V]) finish() {
+	s.done = true
+	s.cancel()
+
+	for range s.results {
+	}
+
+	s.pending = nil
+
+	if s.err != nil {
+		return
+	}
+
+	if s.innerErr != nil {
+		s.err = s.innerErr
+		return
+	}
+
+	if !s.closed {
+		s.err = s.opts.ctx.Err()
+	}
+}
+
+func (s *ParallelMapStream[T, V]) Data() V {
+	return s.current
+}
+
+func (s *ParallelMapStream[T, V]) Err() error {
+	return s.err
+}
+
+func (s *ParallelMapStream[T, V]) Close() error {
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	if s.started && !s.done {
+		s.finish()
+	}
+
+	s.done = true
+
+	return s.inner.Close()
+}
+
+func (s *ParallelMapStream[T, V]) Iter() iter.Seq[V] {
+	return Iter(s)
+}
+
+func (s *ParallelMapStream[T, V]) Iter2() iter.Seq2[V, error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[any] = new(ParallelMapStream[any, any])
@@ -0,0 +1,40 @@
+package streams
+
+import "context"
+
+type ParallelMapOpt func(*parallelMapOpts)
+
+type parallelMapOpts struct {
+	ctx       context.Context
+	unordered bool
+	buffer    int
+}
+
+func (fn ParallelMapOpt) apply(o *parallelMapOpts) {
+	fn(o)
+}
+
+// WithParallelMapContext sets the parent context handed to every mapper call.
+// Cancelling it stops the workers and surfaces ctx.Err() through Err().
+func WithParallelMapContext(ctx context.Context) ParallelMapOpt {
+	return func(o *parallelMapOpts) {
+		o.ctx = ctx
+	}
+}
+
+// WithParallelMapUnordered yields results as soon as they are ready instead
+// of preserving the order of the source stream.
+func WithParallelMapUnordered() ParallelMapOpt {
+	return func(o *parallelMapOpts) {
+		o.unordered = true
+	}
+}
+
+// WithParallelMapBuffer sets how many items may be in flight on top of the
+// number of workers. A larger buffer absorbs latency spikes of single items
+// at the cost of memory. Defaults to the number of workers.
+func WithParallelMapBuffer(size int) ParallelMapOpt {
+	return func(o *parallelMapOpts) {
+		o.buffer = size
+	}
+}
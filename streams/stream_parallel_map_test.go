@@ -0,0 +1,255 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ExampleParallelMap demonstrates mapping stream elements concurrently while
+// preserving the source order.
+func ExampleParallelMap() {
+	// Create a stream from a slice of integers
+	data := []int{1, 2, 3, 4, 5}
+	stream := MemReader(data, nil)
+
+	// Square each number using 3 workers
+	squared := ParallelMap(stream, 3, func(_ context.Context, n int) (int, error) {
+		return n * n, nil
+	})
+
+	// Collect the results
+	result, _ := Consume(squared)
+	fmt.Println(result)
+	// Output: [1 4 9 16 25]
+}
+
+func TestParallelMap_PreservesOrder(t *testing.T) {
+	data := make([]int, 100)
+	for i := range data {
+		data[i] = i
+	}
+
+	stream := ParallelMap(
+		MemReader(data, nil),
+		8,
+		func(_ context.Context, n int) (string, error) {
+			// Earlier items take longer so they finish out of order
+			time.Sleep(time.Duration(100-n) * 10 * time.Microsecond)
+			return fmt.Sprintf("item_%d", n), nil
+		},
+	)
+
+	result, err := Consume(stream)
+	assert.NoError(t, err)
+	assert.Len(t, result, len(data))
+
+	for i, v := range result {
+		assert.Equal(t, fmt.Sprintf("item_%d", i), v)
+	}
+}
+
+func TestParallelMap_Unordered(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	stream := ParallelMap(
+		MemReader(data, nil),
+		4,
+		func(_ context.Context, n int) (int, error) {
+			return n * 10, nil
+		},
+		WithParallelMapUnordered(),
+	)
+
+	result, err := Consume(stream)
+	assert.NoError(t, err)
+
+	sort.Ints(result)
+	assert.Equal(t, []int{10, 20, 30, 40, 50, 60, 70, 80, 90, 100}, result)
+}
+
+func TestParallelMap_MapperErrorCancelsWorkers(t *testing.T) {
+	var (
+		errMapper = errors.New("mapper error")
+		started   = make(chan struct{})
+		cancelled atomic.Bool
+	)
+
+	stream := ParallelMap(
+		MemReader([]int{0, 1, 2, 3}, nil),
+		2,
+		func(ctx context.Context, n int) (int, error) {
+			switch n {
+			case 0:
+				// Blocks until the failure of item 1 cancels it
+				close(started)
+				select {
+				case <-ctx.Done():
+					cancelled.Store(true)
+					return 0, ctx.Err()
+				case <-time.After(time.Second):
+					return n, nil
+				}
+			case 1:
+				<-started
+				return 0, errMapper
+			}
+			return n, nil
+		},
+	)
+
+	result, err := Consume(stream)
+	assert.ErrorIs(t, err, errMapper)
+	assert.Nil(t, result)
+	assert.True(t, cancelled.Load())
+	assert.NoError(t, stream.Close())
+}
+
+func TestParallelMap_InnerError(t *testing.T) {
+	errStream := errors.New("stream error")
+
+	stream := ParallelMap(
+		MemReader([]int{1, 2, 3}, errStream),
+		2,
+		func(_ context.Context, n int) (int, error) {
+			return n, nil
+		},
+	)
+
+	var got []int
+	for stream.Next() {
+		got = append(got, stream.Data())
+	}
+
+	assert.Equal(t, []int{1, 2, 3}, got)
+	assert.ErrorIs(t, stream.Err(), errStream)
+}
+
+func TestParallelMap_ParentContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+
+	stream := ParallelMap(
+		Channel(ch),
+		2,
+		func(_ context.Context, n int) (int, error) {
+			return n, nil
+		},
+		WithParallelMapContext(ctx),
+	)
+
+	go func() {
+		ch <- 1
+		cancel()
+		// Channel receives cannot be interrupted, so unblock the feeder
+		close(ch)
+	}()
+
+	for stream.Next() {
+	}
+
+	assert.ErrorIs(t, stream.Err(), context.Canceled)
+	assert.NoError(t, stream.Close())
+}
+
+func TestParallelMap_ChannelContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := make(chan int)
+
+	stream := ParallelMap(
+		ChannelContext(ctx, ch),
+		2,
+		func(_ context.Context, n int) (int, error) {
+			return n, nil
+		},
+	)
+
+	go func() {
+		ch <- 1
+		cancel()
+	}()
+
+	var got []int
+	for stream.Next() {
+		got = append(got, stream.Data())
+	}
+
+	assert.Equal(t, []int{1}, got)
+	assert.ErrorIs(t, stream.Err(), context.Canceled)
+	assert.NoError(t, stream.Close())
+}
+
+func TestParallelMap_CloseIdleChannel(t *testing.T) {
+	ch := make(chan int)
+	inner := track(Channel(ch))
+
+	stream := ParallelMap[int](
+		inner,
+		2,
+		func(_ context.Context, n int) (int, error) {
+			return n, nil
+		},
+	)
+
+	go func() { ch <- 1 }()
+	require.True(t, stream.Next())
+
+	// The feeder is now blocked waiting for an item that never comes
+	closed := make(chan error)
+	go func() { closed <- stream.Close() }()
+
+	select {
+	case err := <-closed:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Close hung on an idle channel")
+	}
+
+	assert.True(t, inner.closed)
+	assert.False(t, stream.Next())
+	assert.NoError(t, stream.Err())
+}
+
+func TestParallelMap_CloseDrainsWorkers(t *testing.T) {
+	var running atomic.Int32
+
+	data := make([]int, 100)
+
+	stream := ParallelMap(
+		MemReader(data, nil),
+		4,
+		func(ctx context.Context, n int) (int, error) {
+			running.Add(1)
+			defer running.Add(-1)
+			time.Sleep(time.Millisecond)
+			return n, nil
+		},
+	)
+
+	assert.True(t, stream.Next())
+	assert.NoError(t, stream.Close())
+	assert.Equal(t, int32(0), running.Load())
+	assert.False(t, stream.Next())
+	assert.NoError(t, stream.Err())
+}
+
+func TestParallelMap_EmptyStream(t *testing.T) {
+	stream := ParallelMap(
+		MemReader([]int{}, nil),
+		3,
+		func(_ context.Context, n int) (int, error) {
+			return n, nil
+		},
+	)
+
+	result, err := Consume(stream)
+	assert.NoError(t, err)
+	assert.Empty(t, result)
+}
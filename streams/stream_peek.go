@@ -0,0 +1,51 @@
+package streams
+
+import "iter"
+
+// PeekStream calls a function with every item of the inner stream as it is
+// read.
+type PeekStream[T any] struct {
+	inner ReadStream[T]
+	fn    func(T)
+}
+
+// Peek creates a new ReadStream that yields the items of the inner stream
+// unchanged, calling fn with each of them first. It is meant for side effects
+// such as logging or counting in the middle of a pipeline.
+func Peek[T any](inner ReadStream[T], fn func(T)) ReadStream[T] {
+	return &PeekStream[T]{
+		inner: inner,
+		fn:    fn,
+	}
+}
+
+func (s *PeekStream[T]) Next() bool {
+	if !s.inner.Next() {
+		return false
+	}
+
+	s.fn(s.inner.Data())
+	return true
+}
+
+func (s *PeekStream[T]) Data() T {
+	return s.inner.Data()
+}
+
+func (s *PeekStream[T]) Err() error {
+	return s.inner.Err()
+}
+
+func (s *PeekStream[T]) Close() error {
+	return s.inner.Close()
+}
+
+func (s *PeekStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *PeekStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[any] = new(PeekStream[any])
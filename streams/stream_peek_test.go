@@ -0,0 +1,39 @@
+package streams
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeekStream(t *testing.T) {
+	var seen []int
+
+	s := Peek(Filter(MemReader([]int{1, 2, 3, 4}, nil), func(x int) bool { return x%2 == 0 }), func(x int) {
+		seen = append(seen, x)
+	})
+
+	result, err := Consume(s)
+	require.NoError(t, err)
+	assert.Equal(t, []int{2, 4}, result)
+	assert.Equal(t, []int{2, 4}, seen)
+}
+
+// ExamplePeek demonstrates logging items as they flow through a pipeline.
+func ExamplePeek() {
+	numbers := MemReader([]int{1, 2, 3}, nil)
+
+	logged := Peek(numbers, func(x int) {
+		fmt.Println("processing", x)
+	})
+
+	total, _ := Reduce(logged, func(acc, x int) int { return acc + x }, 0)
+	fmt.Println("total:", total)
+	// Output:
+	// processing 1
+	// processing 2
+	// processing 3
+	// total: 6
+}
@@ -0,0 +1,233 @@
+package streams
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+
+	"github.com/sonirico/vago/clock"
+)
+
+type (
+	// tokenBucket refills at rate tokens per second up to burst tokens.
+	// Costs larger than burst are allowed once the bucket is full, leaving it
+	// in debt, so heavy items are delayed instead of rejected.
+	tokenBucket[T any] struct {
+		opts    rateLimitOpts[T]
+		rate    float64
+		burst   float64
+		tokens  float64
+		last    time.Time
+		started bool
+	}
+
+	// RateLimitStream throttles the items of the inner stream.
+	RateLimitStream[T any] struct {
+		inner   ReadStream[T]
+		bucket  *tokenBucket[T]
+		current T
+		err     error
+	}
+
+	// RateLimitWriteStream throttles the writes to the inner stream.
+	RateLimitWriteStream[T any] struct {
+		inner  WriteStream[T]
+		bucket *tokenBucket[T]
+		err    error
+	}
+)
+
+func newTokenBucket[T any](rate float64, burst int, opts []RateLimitOpt[T]) *tokenBucket[T] {
+	optsDef := rateLimitOpts[T]{
+		ctx:   context.Background(),
+		clock: clock.New(),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &tokenBucket[T]{
+		opts:  optsDef,
+		rate:  rate,
+		burst: float64(burst),
+	}
+}
+
+// cost returns how many tokens item takes.
+func (b *tokenBucket[T]) cost(item T) (float64, error) {
+	if b.opts.weightFn == nil {
+		return 1, nil
+	}
+
+	weight := b.opts.weightFn(item)
+	if weight < 0 {
+		return 0, fmt.Errorf("rate limit: negative weight %d", weight)
+	}
+
+	return float64(weight), nil
+}
+
+// take waits until the tokens item costs are available and takes them.
+func (b *tokenBucket[T]) take(item T) error {
+	n, err := b.cost(item)
+	if err != nil {
+		return err
+	}
+	return b.wait(n)
+}
+
+// wait blocks until n tokens are available and takes them.
+func (b *tokenBucket[T]) wait(n float64) error {
+	if b.rate <= 0 {
+		return nil
+	}
+
+	if err := b.opts.ctx.Err(); err != nil {
+		return err
+	}
+
+	if !b.started {
+		b.started = true
+		b.tokens = b.burst
+		b.last = b.opts.clock.Now()
+	}
+
+	need := min(n, b.burst)
+
+	for {
+		now := b.opts.clock.Now()
+		if elapsed := now.Sub(b.last); elapsed > 0 {
+			b.tokens = min(b.burst, b.tokens+elapsed.Seconds()*b.rate)
+			b.last = now
+		}
+
+		if b.tokens >= need {
+			b.tokens -= n
+			return nil
+		}
+
+		delay := time.Duration((need - b.tokens) / b.rate * float64(time.Second))
+		if b.opts.tick > 0 && delay > b.opts.tick {
+			delay = b.opts.tick
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-timer.C:
+		case <-b.opts.ctx.Done():
+			timer.Stop()
+			return b.opts.ctx.Err()
+		}
+	}
+}
+
+// RateLimit creates a new ReadStream that yields the items of the inner stream
+// at most at rate items per second, allowing bursts of up to burst items.
+// A rate of zero or less disables the limit.
+//
+// Use WithRateLimitWeight to limit by a weight, such as bytes, instead of by
+// item count, in which case rate and burst are expressed in that unit.
+func RateLimit[T any](inner ReadStream[T], rate float64, burst int, opts ...RateLimitOpt[T]) ReadStream[T] {
+	return &RateLimitStream[T]{
+		inner:  inner,
+		bucket: newTokenBucket(rate, burst, opts),
+	}
+}
+
+func (s *RateLimitStream[T]) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	if !s.inner.Next() {
+		return false
+	}
+
+	data := s.inner.Data()
+
+	if s.err = s.bucket.take(data); s.err != nil {
+		return false
+	}
+
+	s.current = data
+	return true
+}
+
+func (s *RateLimitStream[T]) Data() T {
+	return s.current
+}
+
+func (s *RateLimitStream[T]) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.inner.Err()
+}
+
+func (s *RateLimitStream[T]) Close() error {
+	return s.inner.Close()
+}
+
+func (s *RateLimitStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *RateLimitStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+// RateLimitWriter creates a new WriteStream that forwards writes to inner at
+// most at rate items per second, allowing bursts of up to burst items. It
+// accepts the same options as RateLimit.
+func RateLimitWriter[T any](inner WriteStream[T], rate float64, burst int, opts ...RateLimitOpt[T]) WriteStream[T] {
+	return &RateLimitWriteStream[T]{
+		inner:  inner,
+		bucket: newTokenBucket(rate, burst, opts),
+	}
+}
+
+// Write waits for the token bucket and writes item to the inner stream
+func (w *RateLimitWriteStream[T]) Write(item T) (int64, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	if w.err = w.bucket.take(item); w.err != nil {
+		return 0, w.err
+	}
+
+	return w.inner.Write(item)
+}
+
+// Flush flushes the inner stream
+func (w *RateLimitWriteStream[T]) Flush() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.inner.Flush()
+}
+
+// Err returns the current error state
+func (w *RateLimitWriteStream[T]) Err() error {
+	if w.err != nil {
+		return w.err
+	}
+	return w.inner.Err()
+}
+
+// Close closes the inner stream
+func (w *RateLimitWriteStream[T]) Close() error {
+	return w.inner.Close()
+}
+
+var (
+	_ ReadStream[any]  = new(RateLimitStream[any])
+	_ WriteStream[any] = new(RateLimitWriteStream[any])
+)
@@ -0,0 +1,56 @@
+package streams
+
+import (
+	"context"
+	"time"
+
+	"github.com/sonirico/vago/clock"
+)
+
+type RateLimitOpt[T any] func(*rateLimitOpts[T])
+
+type rateLimitOpts[T any] struct {
+	ctx      context.Context
+	clock    clock.Clock
+	tick     time.Duration
+	weightFn func(T) int
+}
+
+func (fn RateLimitOpt[T]) apply(o *rateLimitOpts[T]) {
+	fn(o)
+}
+
+// WithRateLimitContext aborts a pending wait once ctx is done, reporting
+// ctx.Err() as the stream error.
+func WithRateLimitContext[T any](ctx context.Context) RateLimitOpt[T] {
+	return func(o *rateLimitOpts[T]) {
+		o.ctx = ctx
+	}
+}
+
+// WithRateLimitClock sets the clock used to refill the token bucket.
+// Use clock.NewMock together with WithRateLimitTick to drive the limiter
+// deterministically in tests.
+func WithRateLimitClock[T any](c clock.Clock) RateLimitOpt[T] {
+	return func(o *rateLimitOpts[T]) {
+		o.clock = c
+	}
+}
+
+// WithRateLimitTick caps how long the limiter sleeps before checking the
+// clock again. Defaults to sleeping for the whole expected wait, which is
+// only accurate with the real clock.
+func WithRateLimitTick[T any](d time.Duration) RateLimitOpt[T] {
+	return func(o *rateLimitOpts[T]) {
+		o.tick = d
+	}
+}
+
+// WithRateLimitWeight makes every item take as many tokens as weightFn
+// returns, e.g. its size in bytes, instead of a single one. A negative weight
+// stops the stream with an error, since it would refill the bucket.
+func WithRateLimitWeight[T any](weightFn func(T) int) RateLimitOpt[T] {
+	return func(o *rateLimitOpts[T]) {
+		o.weightFn = weightFn
+	}
+}
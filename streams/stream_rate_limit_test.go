@@ -0,0 +1,181 @@
+package streams
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// steppingClock advances by step every time it is read, so the limiter makes
+// progress deterministically no matter how long it actually sleeps.
+type steppingClock struct {
+	mu   sync.Mutex
+	now  time.Time
+	step time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(c.step)
+	return c.now
+}
+
+func (c *steppingClock) elapsed(since time.Time) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now.Sub(since)
+}
+
+func TestRateLimitStream(t *testing.T) {
+	const step = 125 * time.Millisecond
+
+	tests := []struct {
+		name       string
+		input      []int
+		rate       float64
+		burst      int
+		opts       []RateLimitOpt[int]
+		minElapsed time.Duration
+	}{
+		{
+			name:       "Within burst",
+			input:      []int{1, 2},
+			rate:       4,
+			burst:      2,
+			minElapsed: 0,
+		},
+		{
+			name:       "Throttled by count",
+			input:      []int{1, 2, 3, 4, 5},
+			rate:       4,
+			burst:      2,
+			minElapsed: 750 * time.Millisecond,
+		},
+		{
+			name:       "Throttled by weight",
+			input:      []int{3, 3},
+			rate:       4,
+			burst:      4,
+			opts:       []RateLimitOpt[int]{WithRateLimitWeight(func(x int) int { return x })},
+			minElapsed: 500 * time.Millisecond,
+		},
+		{
+			name:       "Weight above burst",
+			input:      []int{10, 1},
+			rate:       4,
+			burst:      2,
+			opts:       []RateLimitOpt[int]{WithRateLimitWeight(func(x int) int { return x })},
+			minElapsed: 2250 * time.Millisecond,
+		},
+		{
+			name:       "Unlimited",
+			input:      []int{1, 2, 3, 4, 5},
+			rate:       0,
+			burst:      1,
+			minElapsed: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			clk := &steppingClock{now: start, step: step}
+
+			opts := append([]RateLimitOpt[int]{
+				WithRateLimitClock[int](clk),
+				WithRateLimitTick[int](time.Nanosecond),
+			}, tc.opts...)
+
+			s := RateLimit(MemReader(tc.input, nil), tc.rate, tc.burst, opts...)
+
+			result, err := Consume(s)
+			require.NoError(t, err)
+			assert.Equal(t, tc.input, result)
+
+			// the first read of the clock starts the bucket
+			elapsed := max(clk.elapsed(start)-step, 0)
+			assert.GreaterOrEqual(t, elapsed, tc.minElapsed)
+			assert.Less(t, elapsed, tc.minElapsed+4*step)
+		})
+	}
+}
+
+func TestRateLimitStream_Context(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	s := RateLimit(MemReader([]int{1, 2, 3}, nil), 0.001, 1, WithRateLimitContext[int](ctx))
+
+	require.True(t, s.Next())
+	assert.False(t, s.Next())
+	assert.ErrorIs(t, s.Err(), context.DeadlineExceeded)
+}
+
+func TestRateLimit_NegativeWeight(t *testing.T) {
+	weight := WithRateLimitWeight(func(x int) int { return x })
+
+	s := RateLimit(MemReader([]int{1, -5, 1}, nil), 1, 1, weight)
+
+	require.True(t, s.Next())
+	assert.False(t, s.Next())
+	assert.ErrorContains(t, s.Err(), "negative weight -5")
+
+	dst := MemWriter[int]()
+	w := RateLimitWriter[int](dst, 1, 1, weight)
+
+	_, err := w.Write(-1)
+	assert.ErrorContains(t, err, "negative weight -1")
+	assert.ErrorContains(t, w.Err(), "negative weight -1")
+	assert.Empty(t, dst.Items())
+}
+
+func TestRateLimitWriter(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clk := &steppingClock{now: start, step: 125 * time.Millisecond}
+
+	dst := MemWriter[string]()
+	w := RateLimitWriter[string](dst, 4, 1,
+		WithRateLimitClock[string](clk),
+		WithRateLimitTick[string](time.Nanosecond),
+		WithRateLimitWeight(func(s string) int { return len(s) }),
+	)
+
+	written, err := Pipe(MemReader([]string{"a", "bb", "c"}, nil), w)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), written)
+	assert.Equal(t, []string{"a", "bb", "c"}, dst.Items())
+
+	// "bb" waits for a full bucket and leaves it in debt for "c"
+	assert.GreaterOrEqual(t, clk.elapsed(start), 500*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	w = RateLimitWriter(dst, 1, 1, WithRateLimitContext[string](ctx))
+	_, err = w.Write("d")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, w.Err(), context.Canceled)
+}
+
+// ExampleRateLimit demonstrates throttling calls to a partner API.
+func ExampleRateLimit() {
+	requests := MemReader([]string{"GET /a", "GET /b", "GET /c"}, nil)
+
+	// At most 50 requests per second, in bursts of up to 2
+	stream := RateLimit(requests, 50, 2)
+	defer stream.Close()
+
+	for stream.Next() {
+		fmt.Println(stream.Data())
+	}
+	// Output:
+	// GET /a
+	// GET /b
+	// GET /c
+}
@@ -2,15 +2,18 @@ package streams
 
 import (
 	"bufio"
+	"context"
 	"io"
 )
 
 // ReaderStream is a ReadStream implementation that reads from an io.Reader
 // This is useful for reading streams of bytes from files, network connections, etc.
 type ReaderStream struct {
+	ctx      context.Context
 	original io.Reader
 	reader   *bufio.Reader
 	current  []byte
+	offset   int64
 	err      error
 	closed   bool
 }
@@ -21,8 +24,13 @@ func (r *ReaderStream) Next() bool {
 		return false
 	}
 
+	if r.err = r.ctx.Err(); r.err != nil {
+		return false
+	}
+
 	// Read a line or chunk of data
 	line, err := r.reader.ReadBytes('\n')
+	r.offset += int64(len(line))
 	if err != nil {
 		r.err = err
 		if err == io.EOF && len(line) > 0 {
@@ -42,6 +50,12 @@ func (r *ReaderStream) Data() []byte {
 	return r.current
 }
 
+// Offset returns the number of bytes consumed from the reader, up to and
+// including the current chunk.
+func (r *ReaderStream) Offset() int64 {
+	return r.offset
+}
+
 // Err returns the current error state
 func (r *ReaderStream) Err() error {
 	return r.err
@@ -63,7 +77,14 @@ func (r *ReaderStream) Close() error {
 
 // Reader creates a new ReadStream that reads from an io.Reader
 func Reader(reader io.Reader) ReadStream[[]byte] {
+	return ReaderContext(context.Background(), reader)
+}
+
+// ReaderContext is like Reader but stops reading once ctx is done, reporting
+// ctx.Err() through Err.
+func ReaderContext(ctx context.Context, reader io.Reader) ReadStream[[]byte] {
 	return &ReaderStream{
+		ctx:      ctx,
 		original: reader,
 		reader:   bufio.NewReader(reader),
 	}
@@ -0,0 +1,115 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrNoOffset is returned by ResumablePipe when the source does not report
+// its offset.
+var ErrNoOffset = errors.New("stream does not report its offset")
+
+// OffsetReadStream is a ReadStream that knows how many bytes of its input it
+// has consumed, such as the streams returned by Reader, Lines, JSON and CSV.
+//
+// Offsets are relative to the position the input was at when the stream was
+// created.
+type OffsetReadStream[T any] interface {
+	ReadStream[T]
+	Offset() int64
+}
+
+// ResumablePipe is like Pipe, but periodically commits the offset of the
+// source to checkpointer, and resumes from the last committed offset when
+// run again.
+//
+// open is called with the offset to resume from, and must return a stream
+// over the input starting at that offset, e.g. by seeking a file. The stream
+// must implement OffsetReadStream. It is closed when the pipe returns.
+//
+// An offset is committed only once dst has been flushed, so items are
+// delivered at least once: after a crash, those written since the last
+// checkpoint are written again.
+func ResumablePipe[T any](
+	open func(offset int64) (ReadStream[T], error),
+	dst WriteStream[T],
+	checkpointer Checkpointer,
+	opts ...ResumableOpt,
+) (int64, error) {
+	optsDef := resumableOpts{
+		ctx:   context.Background(),
+		every: 1000,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	if optsDef.every < 1 {
+		optsDef.every = 1
+	}
+
+	ctx := optsDef.ctx
+
+	base, err := checkpointer.Load(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("checkpoint error: %w", err)
+	}
+
+	stream, err := open(base)
+	if err != nil {
+		return 0, err
+	}
+
+	defer stream.Close()
+
+	src, ok := stream.(OffsetReadStream[T])
+	if !ok {
+		return 0, ErrNoOffset
+	}
+
+	commit := func() error {
+		if err := dst.Flush(); err != nil {
+			return fmt.Errorf("flush error: %w", err)
+		}
+
+		if err := checkpointer.Save(ctx, base+src.Offset()); err != nil {
+			return fmt.Errorf("checkpoint error: %w", err)
+		}
+
+		return nil
+	}
+
+	var (
+		totalBytes int64
+		pending    int
+	)
+
+	for src.Next() {
+		if err := ctx.Err(); err != nil {
+			return totalBytes, err
+		}
+
+		n, err := dst.Write(src.Data())
+		if err != nil {
+			return totalBytes, fmt.Errorf("write error: %w", err)
+		}
+		totalBytes += n
+
+		if pending++; pending >= optsDef.every {
+			pending = 0
+
+			if err := commit(); err != nil {
+				return totalBytes, err
+			}
+		}
+	}
+
+	if err := src.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return totalBytes, fmt.Errorf("read error: %w", err)
+	}
+
+	return totalBytes, commit()
+}
@@ -0,0 +1,30 @@
+package streams
+
+import "context"
+
+type ResumableOpt func(*resumableOpts)
+
+type resumableOpts struct {
+	ctx   context.Context
+	every int
+}
+
+func (fn ResumableOpt) apply(o *resumableOpts) {
+	fn(o)
+}
+
+// WithResumableContext stops the pipe once ctx is done, returning ctx.Err().
+// Items written since the last checkpoint are not committed.
+func WithResumableContext(ctx context.Context) ResumableOpt {
+	return func(o *resumableOpts) {
+		o.ctx = ctx
+	}
+}
+
+// WithResumableEvery sets how many items are written between checkpoints.
+// Defaults to 1000.
+func WithResumableEvery(items int) ResumableOpt {
+	return func(o *resumableOpts) {
+		o.every = items
+	}
+}
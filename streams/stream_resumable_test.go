@@ -0,0 +1,200 @@
+package streams
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memCheckpointer struct {
+	offset int64
+	saves  []int64
+	err    error
+}
+
+func (c *memCheckpointer) Load(context.Context) (int64, error) {
+	return c.offset, nil
+}
+
+func (c *memCheckpointer) Save(_ context.Context, offset int64) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.offset = offset
+	c.saves = append(c.saves, offset)
+	return nil
+}
+
+// crashingWriter fails every write after the first limit ones.
+type crashingWriter[T any] struct {
+	*MemoryWriteStream[T]
+	limit int
+}
+
+func (w *crashingWriter[T]) Write(item T) (int64, error) {
+	if len(w.Items()) >= w.limit {
+		return 0, errors.New("crashed")
+	}
+	return w.MemoryWriteStream.Write(item)
+}
+
+func openLinesAt(data string) func(int64) (ReadStream[string], error) {
+	return func(offset int64) (ReadStream[string], error) {
+		r := strings.NewReader(data)
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return Lines(r), nil
+	}
+}
+
+func TestOffsets(t *testing.T) {
+	t.Run("Lines", func(t *testing.T) {
+		s := Lines(strings.NewReader("a\r\nbb\nccc"))
+
+		var offsets []int64
+		for s.Next() {
+			offsets = append(offsets, s.(OffsetReadStream[string]).Offset())
+		}
+		assert.Equal(t, []int64{3, 6, 9}, offsets)
+	})
+
+	t.Run("Reader", func(t *testing.T) {
+		s := Reader(strings.NewReader("a\nbb\n"))
+
+		var offsets []int64
+		for s.Next() {
+			offsets = append(offsets, s.(OffsetReadStream[[]byte]).Offset())
+		}
+		assert.Equal(t, []int64{2, 5}, offsets)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		s := JSON[map[string]int](io.NopCloser(strings.NewReader(`{"a":1}` + "\n" + `{"a":22}` + "\n")))
+
+		var offsets []int64
+		for s.Next() {
+			offsets = append(offsets, s.Offset())
+		}
+		assert.Equal(t, []int64{7, 16}, offsets)
+	})
+
+	t.Run("CSV skips the BOM", func(t *testing.T) {
+		s, err := CSV[[]string](WithCSVReader(io.NopCloser(strings.NewReader("\xEF\xBB\xBFa,b\nc,d\n"))))
+		require.NoError(t, err)
+
+		var offsets []int64
+		for s.Next() {
+			offsets = append(offsets, s.Offset())
+		}
+		assert.Equal(t, []int64{7, 11}, offsets)
+	})
+}
+
+func TestResumablePipe(t *testing.T) {
+	const data = "1\n2\n3\n4\n5\n"
+
+	t.Run("Resumes after a crash", func(t *testing.T) {
+		cp := new(memCheckpointer)
+
+		first := &crashingWriter[string]{MemoryWriteStream: MemWriter[string](), limit: 3}
+		_, err := ResumablePipe(openLinesAt(data), first, cp, WithResumableEvery(2))
+		assert.ErrorContains(t, err, "write error: crashed")
+		assert.Equal(t, []int64{4}, cp.saves)
+
+		second := MemWriter[string]()
+		n, err := ResumablePipe(openLinesAt(data), second, cp, WithResumableEvery(2))
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), n)
+
+		// 3 was written by the first run but not committed, so it is
+		// delivered again
+		assert.Equal(t, []string{"1", "2", "3"}, first.Items())
+		assert.Equal(t, []string{"3", "4", "5"}, second.Items())
+		assert.Equal(t, int64(len(data)), cp.offset)
+	})
+
+	t.Run("Finished job does nothing", func(t *testing.T) {
+		cp := &memCheckpointer{offset: int64(len(data))}
+		dst := MemWriter[string]()
+
+		n, err := ResumablePipe(openLinesAt(data), dst, cp)
+		require.NoError(t, err)
+		assert.Zero(t, n)
+		assert.Empty(t, dst.Items())
+	})
+
+	t.Run("Failed writes are not committed", func(t *testing.T) {
+		cp := new(memCheckpointer)
+		dst := MemWriter[string]()
+		dst.SetError(errors.New("disk full"))
+
+		_, err := ResumablePipe(openLinesAt(data), dst, cp)
+		assert.ErrorContains(t, err, "disk full")
+		assert.Empty(t, cp.saves)
+	})
+
+	t.Run("Checkpoint failure", func(t *testing.T) {
+		cp := &memCheckpointer{err: errors.New("unavailable")}
+
+		_, err := ResumablePipe(openLinesAt(data), MemWriter[string](), cp)
+		assert.ErrorContains(t, err, "checkpoint error: unavailable")
+	})
+
+	t.Run("Requires offsets", func(t *testing.T) {
+		open := func(int64) (ReadStream[int], error) {
+			return MemReader([]int{1}, nil), nil
+		}
+
+		_, err := ResumablePipe(open, MemWriter[int](), new(memCheckpointer))
+		assert.ErrorIs(t, err, ErrNoOffset)
+	})
+
+	t.Run("Context stops the pipe", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		cp := new(memCheckpointer)
+		_, err := ResumablePipe(openLinesAt(data), MemWriter[string](), cp, WithResumableContext(ctx))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, cp.saves)
+	})
+}
+
+// ExampleResumablePipe demonstrates resuming a pipe from its last checkpoint.
+func ExampleResumablePipe() {
+	const input = "a\nb\nc\nd\n"
+
+	open := func(offset int64) (ReadStream[string], error) {
+		r := strings.NewReader(input)
+		if _, err := r.Seek(offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return Lines(r), nil
+	}
+
+	// A previous run committed the first two lines
+	path := filepath.Join(os.TempDir(), "example-resumable.offset")
+	defer os.Remove(path)
+
+	checkpoints := NewFileCheckpointer(path)
+	_ = checkpoints.Save(context.Background(), 4)
+
+	dst := MemWriter[string]()
+	if _, err := ResumablePipe(open, dst, checkpoints, WithResumableEvery(1)); err != nil {
+		fmt.Println("error:", err)
+	}
+
+	offset, _ := checkpoints.Load(context.Background())
+	fmt.Println(dst.Items(), offset)
+	// Output:
+	// [c d] 8
+}
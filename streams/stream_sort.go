@@ -0,0 +1,267 @@
+package streams
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"slices"
+)
+
+type (
+	// SortStream sorts the inner stream with an external merge sort: items
+	// are buffered up to a memory budget, spilled to disk as sorted runs and
+	// merged back once the inner stream ends.
+	SortStream[T any] struct {
+		inner   ReadStream[T]
+		less    func(a, b T) bool
+		opts    sortOpts
+		runs    []ReadStream[T]
+		merged  ReadStream[T]
+		current T
+		err     error
+		started bool
+		closed  bool
+	}
+
+	// sortRun reads back a run spilled to a temporary file, removing the
+	// file once closed.
+	sortRun[T any] struct {
+		file    *os.File
+		reader  *bufio.Reader
+		codec   SortCodec
+		buf     []byte
+		current T
+		err     error
+	}
+
+	jsonSortCodec struct{}
+)
+
+// Sort creates a new ReadStream that yields the items of the inner stream
+// sorted according to less, keeping the order of equal items. It works on
+// streams larger than memory, e.g. to sort an export before Group, since at
+// most WithSortBudget items are buffered and the rest is spilled to temporary
+// files.
+//
+// Nothing is yielded until the inner stream ends. Close removes any
+// temporary file left and closes the inner stream.
+func Sort[T any](inner ReadStream[T], less func(a, b T) bool, opts ...SortOpt) ReadStream[T] {
+	optsDef := sortOpts{
+		budget: 100_000,
+		codec:  jsonSortCodec{},
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	if optsDef.budget < 1 {
+		optsDef.budget = 1
+	}
+
+	return &SortStream[T]{
+		inner: inner,
+		less:  less,
+		opts:  optsDef,
+	}
+}
+
+func (s *SortStream[T]) Next() bool {
+	if s.err != nil || s.closed {
+		return false
+	}
+
+	if !s.started {
+		s.started = true
+
+		if s.err = s.sortRuns(); s.err != nil {
+			return false
+		}
+
+		s.merged = MergeSorted(s.less, s.runs...)
+	}
+
+	if !s.merged.Next() {
+		s.err = s.merged.Err()
+		return false
+	}
+
+	s.current = s.merged.Data()
+	return true
+}
+
+// sortRuns consumes the inner stream into sorted runs. The last run is kept
+// in memory.
+func (s *SortStream[T]) sortRuns() error {
+	buf := make([]T, 0, min(s.opts.budget, 1024))
+
+	for s.inner.Next() {
+		if len(buf) == s.opts.budget {
+			if err := s.spill(buf); err != nil {
+				return err
+			}
+			buf = buf[:0]
+		}
+
+		buf = append(buf, s.inner.Data())
+	}
+
+	if err := s.inner.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	slices.SortStableFunc(buf, s.compare)
+	s.runs = append(s.runs, MemReader(buf, nil))
+
+	return nil
+}
+
+// spill sorts buf and writes it to a temporary file as a new run.
+func (s *SortStream[T]) spill(buf []T) error {
+	slices.SortStableFunc(buf, s.compare)
+
+	file, err := os.CreateTemp(s.opts.tempDir, "vago-sort-*")
+	if err != nil {
+		return fmt.Errorf("sort spill: %w", err)
+	}
+
+	run := &sortRun[T]{file: file, codec: s.opts.codec}
+	s.runs = append(s.runs, run)
+
+	w := bufio.NewWriter(file)
+	size := make([]byte, binary.MaxVarintLen64)
+
+	for _, item := range buf {
+		data, err := s.opts.codec.Encode(item)
+		if err != nil {
+			return fmt.Errorf("sort spill: %w", err)
+		}
+
+		n := binary.PutUvarint(size, uint64(len(data)))
+		if _, err := w.Write(size[:n]); err != nil {
+			return fmt.Errorf("sort spill: %w", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("sort spill: %w", err)
+		}
+	}
+
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("sort spill: %w", err)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("sort spill: %w", err)
+	}
+
+	run.reader = bufio.NewReader(file)
+	return nil
+}
+
+func (s *SortStream[T]) compare(a, b T) int {
+	switch {
+	case s.less(a, b):
+		return -1
+	case s.less(b, a):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (s *SortStream[T]) Data() T {
+	return s.current
+}
+
+func (s *SortStream[T]) Err() error {
+	return s.err
+}
+
+// Close removes the spilled runs and closes the inner stream.
+func (s *SortStream[T]) Close() error {
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	errs := make([]error, 0, len(s.runs)+1)
+	for _, run := range s.runs {
+		errs = append(errs, run.Close())
+	}
+	s.runs = nil
+
+	errs = append(errs, s.inner.Close())
+
+	return errors.Join(errs...)
+}
+
+func (s *SortStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *SortStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+func (r *sortRun[T]) Next() bool {
+	if r.err != nil || r.reader == nil {
+		return false
+	}
+
+	size, err := binary.ReadUvarint(r.reader)
+	if err != nil {
+		if !errors.Is(err, io.EOF) {
+			r.err = fmt.Errorf("sort run: %w", err)
+		}
+		return false
+	}
+
+	r.buf = slices.Grow(r.buf[:0], int(size))[:size]
+	if _, err := io.ReadFull(r.reader, r.buf); err != nil {
+		r.err = fmt.Errorf("sort run: %w", err)
+		return false
+	}
+
+	var item T
+	if err := r.codec.Decode(r.buf, &item); err != nil {
+		r.err = fmt.Errorf("sort run: %w", err)
+		return false
+	}
+
+	r.current = item
+	return true
+}
+
+func (r *sortRun[T]) Data() T {
+	return r.current
+}
+
+func (r *sortRun[T]) Err() error {
+	return r.err
+}
+
+func (r *sortRun[T]) Close() error {
+	if r.file == nil {
+		return nil
+	}
+
+	err := errors.Join(r.file.Close(), os.Remove(r.file.Name()))
+	r.file, r.reader = nil, nil
+	return err
+}
+
+func (jsonSortCodec) Encode(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonSortCodec) Decode(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+var _ ReadStream[any] = new(SortStream[any])
@@ -0,0 +1,45 @@
+package streams
+
+type SortOpt func(*sortOpts)
+
+// SortCodec encodes the items spilled to disk by Sort. Any codec.Codec, such
+// as the gob or msgpack ones, satisfies it.
+type SortCodec interface {
+	Encode(any) ([]byte, error)
+	Decode([]byte, any) error
+}
+
+type sortOpts struct {
+	budget  int
+	codec   SortCodec
+	tempDir string
+}
+
+func (fn SortOpt) apply(o *sortOpts) {
+	fn(o)
+}
+
+// WithSortBudget sets how many items are held in memory at once. Every time
+// the budget is reached, the buffered items are sorted and spilled to a
+// temporary file as a run. Defaults to 100000.
+func WithSortBudget(items int) SortOpt {
+	return func(o *sortOpts) {
+		o.budget = items
+	}
+}
+
+// WithSortCodec sets the codec used to encode spilled items. Defaults to
+// JSON, so only exported fields survive a spill.
+func WithSortCodec(codec SortCodec) SortOpt {
+	return func(o *sortOpts) {
+		o.codec = codec
+	}
+}
+
+// WithSortTempDir sets the directory where runs are spilled. Defaults to
+// os.TempDir.
+func WithSortTempDir(dir string) SortOpt {
+	return func(o *sortOpts) {
+		o.tempDir = dir
+	}
+}
@@ -0,0 +1,141 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type sortRecord struct {
+	Key   string
+	Value int
+}
+
+type failingCodec struct {
+	jsonSortCodec
+}
+
+func (failingCodec) Encode(any) ([]byte, error) {
+	return nil, errors.New("cannot encode")
+}
+
+func TestSortStream(t *testing.T) {
+	byKey := func(a, b sortRecord) bool { return a.Key < b.Key }
+
+	input := []sortRecord{
+		{"c", 1}, {"a", 2}, {"b", 3}, {"a", 4}, {"c", 5}, {"b", 6}, {"a", 7},
+	}
+	expected := []sortRecord{
+		{"a", 2}, {"a", 4}, {"a", 7}, {"b", 3}, {"b", 6}, {"c", 1}, {"c", 5},
+	}
+
+	tests := []struct {
+		name   string
+		budget int
+		files  int
+	}{
+		{"Fits in memory", 100, 0},
+		{"Spills runs", 2, 3},
+		{"Spills every item", 1, 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			s := Sort(MemReader(input, nil), byKey, WithSortBudget(tt.budget), WithSortTempDir(dir))
+
+			var got []sortRecord
+			for s.Next() {
+				got = append(got, s.Data())
+
+				entries, err := os.ReadDir(dir)
+				require.NoError(t, err)
+				assert.Len(t, entries, tt.files)
+			}
+
+			require.NoError(t, s.Err())
+			assert.Equal(t, expected, got)
+
+			require.NoError(t, s.Close())
+			require.NoError(t, s.Close())
+
+			entries, err := os.ReadDir(dir)
+			require.NoError(t, err)
+			assert.Empty(t, entries, "runs are removed on Close")
+		})
+	}
+
+	t.Run("Empty stream", func(t *testing.T) {
+		result, err := Consume(Sort(MemReader([]int(nil), nil), func(a, b int) bool { return a < b }))
+		require.NoError(t, err)
+		assert.Empty(t, result)
+	})
+
+	t.Run("Inner error", func(t *testing.T) {
+		dir := t.TempDir()
+		boom := errors.New("boom")
+
+		s := Sort(MemReader(input, boom), byKey, WithSortBudget(1), WithSortTempDir(dir))
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), boom)
+		require.NoError(t, s.Close())
+	})
+
+	t.Run("Codec error", func(t *testing.T) {
+		dir := t.TempDir()
+
+		s := Sort(MemReader(input, nil), byKey,
+			WithSortBudget(2),
+			WithSortTempDir(dir),
+			WithSortCodec(failingCodec{}),
+		)
+		assert.False(t, s.Next())
+		assert.ErrorContains(t, s.Err(), "sort spill: cannot encode")
+
+		require.NoError(t, s.Close())
+
+		entries, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Close closes the inner stream", func(t *testing.T) {
+		inner := track(MemReader([]int{2, 1}, nil))
+
+		s := Sort[int](inner, func(a, b int) bool { return a < b })
+		require.NoError(t, s.Close())
+		assert.True(t, inner.closed)
+	})
+}
+
+// ExampleSort demonstrates sorting a stream larger than the memory budget before grouping it.
+func ExampleSort() {
+	type sale struct {
+		Region string
+		Amount int
+	}
+
+	sales := MemReader([]sale{
+		{"eu", 10}, {"us", 5}, {"eu", 7}, {"apac", 3}, {"us", 1},
+	}, nil)
+
+	// Only two sales are held in memory, the rest is spilled to disk
+	sorted := Sort(sales, func(a, b sale) bool { return a.Region < b.Region }, WithSortBudget(2))
+	defer sorted.Close()
+
+	for sorted.Next() {
+		s := sorted.Data()
+		fmt.Println(s.Region, s.Amount)
+	}
+	// Output:
+	// apac 3
+	// eu 10
+	// eu 7
+	// us 5
+	// us 1
+}
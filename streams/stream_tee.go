@@ -0,0 +1,124 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// TeeStream mirrors the items of the inner stream into a sink as they are
+// read.
+type TeeStream[T any] struct {
+	inner    ReadStream[T]
+	sink     WriteStream[T]
+	opts     teeOpts
+	current  T
+	err      error
+	detached bool
+	flushed  bool
+}
+
+// Tee creates a new ReadStream that yields the items of the inner stream
+// unchanged, writing each of them to sink first, e.g. a MemWriter in tests or
+// a JSONEachRow file to debug a stage of a pipeline.
+//
+// The sink is flushed once the inner stream ends, or on Close. It is not
+// closed, as it belongs to the caller. Sink errors are handled according to
+// WithTeePolicy.
+func Tee[T any](inner ReadStream[T], sink WriteStream[T], opts ...TeeOpt) ReadStream[T] {
+	optsDef := teeOpts{
+		policy: TeeFailFast,
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	return &TeeStream[T]{
+		inner: inner,
+		sink:  sink,
+		opts:  optsDef,
+	}
+}
+
+func (s *TeeStream[T]) Next() bool {
+	if s.err != nil {
+		return false
+	}
+
+	if !s.inner.Next() {
+		s.err = s.flush()
+		return false
+	}
+
+	s.current = s.inner.Data()
+
+	if !s.detached {
+		if _, err := s.sink.Write(s.current); err != nil {
+			if s.err = s.handle(fmt.Errorf("tee write: %w", err)); s.err != nil {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// flush flushes the sink once, when the stream ends or is closed.
+func (s *TeeStream[T]) flush() error {
+	if s.flushed || s.detached {
+		return nil
+	}
+
+	s.flushed = true
+
+	if err := s.sink.Flush(); err != nil {
+		return s.handle(fmt.Errorf("tee flush: %w", err))
+	}
+
+	return nil
+}
+
+// handle applies the policy to a sink error, returning it if it must stop
+// the stream.
+func (s *TeeStream[T]) handle(err error) error {
+	switch s.opts.policy {
+	case TeeDetach:
+		s.detached = true
+	case TeeIgnore:
+	default:
+		return err
+	}
+
+	if s.opts.onError != nil {
+		s.opts.onError(err)
+	}
+
+	return nil
+}
+
+func (s *TeeStream[T]) Data() T {
+	return s.current
+}
+
+func (s *TeeStream[T]) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.inner.Err()
+}
+
+// Close flushes the sink and closes the inner stream.
+func (s *TeeStream[T]) Close() error {
+	return errors.Join(s.flush(), s.inner.Close())
+}
+
+func (s *TeeStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *TeeStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[any] = new(TeeStream[any])
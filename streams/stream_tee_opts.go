@@ -0,0 +1,42 @@
+package streams
+
+// TeePolicy decides what Tee does when its sink fails.
+type TeePolicy int
+
+const (
+	// TeeFailFast stops the stream on the first sink error, reported by Err.
+	TeeFailFast TeePolicy = iota
+	// TeeDetach stops mirroring into the sink after its first error, while
+	// the main flow carries on.
+	TeeDetach
+	// TeeIgnore keeps mirroring every item into the sink regardless of its
+	// errors.
+	TeeIgnore
+)
+
+type TeeOpt func(*teeOpts)
+
+type teeOpts struct {
+	policy  TeePolicy
+	onError func(error)
+}
+
+func (fn TeeOpt) apply(o *teeOpts) {
+	fn(o)
+}
+
+// WithTeePolicy sets what happens when the sink fails. Defaults to
+// TeeFailFast.
+func WithTeePolicy(policy TeePolicy) TeeOpt {
+	return func(o *teeOpts) {
+		o.policy = policy
+	}
+}
+
+// WithTeeOnError calls fn with every sink error that does not stop the
+// stream, e.g. to log them.
+func WithTeeOnError(fn func(error)) TeeOpt {
+	return func(o *teeOpts) {
+		o.onError = fn
+	}
+}
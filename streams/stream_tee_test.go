@@ -0,0 +1,110 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flushCounter counts the flushes of the wrapped MemoryWriteStream.
+type flushCounter[T any] struct {
+	*MemoryWriteStream[T]
+	flushes  int
+	flushErr error
+}
+
+func (w *flushCounter[T]) Flush() error {
+	w.flushes++
+	return w.flushErr
+}
+
+func TestTeeStream(t *testing.T) {
+	failSecond := func(call int) bool { return call == 2 }
+
+	tests := []struct {
+		name     string
+		policy   TeePolicy
+		main     []int
+		mirrored []int
+		err      string
+		reported int
+	}{
+		{"Fail fast", TeeFailFast, []int{1}, []int{1}, "tee write: sink unavailable", 0},
+		{"Detach", TeeDetach, []int{1, 2, 3, 4}, []int{1}, "", 1},
+		{"Ignore", TeeIgnore, []int{1, 2, 3, 4}, []int{1, 3, 4}, "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &flakyWriter[int]{failOn: failSecond}
+			reported := 0
+
+			s := Tee[int](MemReader([]int{1, 2, 3, 4}, nil), sink,
+				WithTeePolicy(tt.policy),
+				WithTeeOnError(func(error) { reported++ }),
+			)
+
+			var got []int
+			for s.Next() {
+				got = append(got, s.Data())
+			}
+
+			assert.Equal(t, tt.main, got)
+			assert.Equal(t, tt.mirrored, sink.Items())
+			assert.Equal(t, tt.reported, reported)
+
+			if tt.err == "" {
+				assert.NoError(t, s.Err())
+			} else {
+				assert.EqualError(t, s.Err(), tt.err)
+			}
+		})
+	}
+
+	t.Run("Flushes the sink once", func(t *testing.T) {
+		sink := &flushCounter[int]{MemoryWriteStream: MemWriter[int]()}
+
+		s := Tee[int](MemReader([]int{1, 2}, nil), sink)
+		result, err := Consume(s)
+		require.NoError(t, err)
+		require.NoError(t, s.Close())
+
+		assert.Equal(t, []int{1, 2}, result)
+		assert.Equal(t, []int{1, 2}, sink.Items())
+		assert.Equal(t, 1, sink.flushes)
+	})
+
+	t.Run("Flush error", func(t *testing.T) {
+		sink := &flushCounter[int]{MemoryWriteStream: MemWriter[int](), flushErr: errors.New("disk full")}
+
+		s := Tee[int](MemReader([]int{1}, nil), sink)
+		assert.True(t, s.Next())
+		assert.EqualError(t, s.Close(), "tee flush: disk full")
+	})
+
+	t.Run("Inner error", func(t *testing.T) {
+		boom := errors.New("boom")
+
+		s := Tee[int](MemReader([]int(nil), boom), MemWriter[int]())
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), boom)
+	})
+}
+
+// ExampleTee demonstrates inspecting an intermediate stage of a pipeline.
+func ExampleTee() {
+	debug := MemWriter[int]()
+
+	evens := Filter(MemReader([]int{1, 2, 3, 4, 5, 6}, nil), func(x int) bool { return x%2 == 0 })
+	squares := Map(Tee(evens, debug), func(x int) int { return x * x })
+
+	result, _ := Consume(squares)
+	fmt.Println("after filter:", debug.Items())
+	fmt.Println("result:", result)
+	// Output:
+	// after filter: [2 4 6]
+	// result: [4 16 36]
+}
@@ -0,0 +1,178 @@
+package streams
+
+import (
+	"container/heap"
+	"errors"
+	"io"
+	"iter"
+	"sort"
+)
+
+type (
+	topKItem[T any] struct {
+		value T
+		seq   uint64
+	}
+
+	// topKHeap is a min-heap, so its root is the first item to be replaced
+	// by a greater one. Among equal items, the latest one is replaced first.
+	topKHeap[T any] struct {
+		items []topKItem[T]
+		less  func(a, b T) bool
+	}
+
+	// TopKStream keeps the k greatest items of the inner stream and yields
+	// them once it is exhausted.
+	TopKStream[T any] struct {
+		inner   ReadStream[T]
+		k       int
+		queue   *topKHeap[T]
+		result  []T
+		pos     int
+		current T
+		err     error
+		started bool
+	}
+)
+
+// TopK creates a new ReadStream that yields the k greatest items of the inner
+// stream according to less, from greatest to smallest, once the inner stream
+// ends. Equal items keep their source order. Only k items are held in memory.
+//
+// Nothing is yielded if the inner stream fails with an error other than
+// io.EOF, since a partial ranking would be misleading; the error is reported
+// by Err instead.
+func TopK[T any](inner ReadStream[T], k int, less func(a, b T) bool) ReadStream[T] {
+	if k < 0 {
+		k = 0
+	}
+
+	return &TopKStream[T]{
+		inner: inner,
+		k:     k,
+		queue: &topKHeap[T]{
+			items: make([]topKItem[T], 0, k),
+			less:  less,
+		},
+	}
+}
+
+func (s *TopKStream[T]) Next() bool {
+	if !s.started {
+		s.started = true
+
+		if !s.rank() {
+			return false
+		}
+	}
+
+	if s.pos >= len(s.result) {
+		return false
+	}
+
+	s.current = s.result[s.pos]
+	s.pos++
+	return true
+}
+
+// rank consumes the inner stream and sorts the retained items.
+func (s *TopKStream[T]) rank() bool {
+	var seq uint64
+
+	for s.inner.Next() {
+		item := topKItem[T]{value: s.inner.Data(), seq: seq}
+		seq++
+
+		switch {
+		case s.k == 0:
+		case s.queue.Len() < s.k:
+			heap.Push(s.queue, item)
+		case s.queue.less(s.queue.items[0].value, item.value):
+			s.queue.items[0] = item
+			heap.Fix(s.queue, 0)
+		}
+	}
+
+	// io.EOF, as reported by JSON streams, is a clean end of the input
+	if err := s.inner.Err(); err != nil && !errors.Is(err, io.EOF) {
+		s.err = err
+		s.queue = nil
+		return false
+	}
+
+	items, less := s.queue.items, s.queue.less
+	s.queue = nil
+
+	sort.Slice(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+
+		if less(b.value, a.value) {
+			return true
+		}
+		if less(a.value, b.value) {
+			return false
+		}
+		return a.seq < b.seq
+	})
+
+	s.result = make([]T, len(items))
+	for i, item := range items {
+		s.result[i] = item.value
+	}
+
+	return true
+}
+
+func (s *TopKStream[T]) Data() T {
+	return s.current
+}
+
+func (s *TopKStream[T]) Err() error {
+	return s.err
+}
+
+func (s *TopKStream[T]) Close() error {
+	return s.inner.Close()
+}
+
+func (s *TopKStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *TopKStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+func (h *topKHeap[T]) Len() int {
+	return len(h.items)
+}
+
+func (h *topKHeap[T]) Less(i, j int) bool {
+	a, b := h.items[i], h.items[j]
+
+	if h.less(a.value, b.value) {
+		return true
+	}
+	if h.less(b.value, a.value) {
+		return false
+	}
+	return a.seq > b.seq
+}
+
+func (h *topKHeap[T]) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+}
+
+func (h *topKHeap[T]) Push(x any) {
+	h.items = append(h.items, x.(topKItem[T]))
+}
+
+func (h *topKHeap[T]) Pop() any {
+	n := len(h.items)
+	x := h.items[n-1]
+	h.items[n-1] = topKItem[T]{}
+	h.items = h.items[:n-1]
+	return x
+}
+
+var _ ReadStream[any] = new(TopKStream[any])
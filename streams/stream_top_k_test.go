@@ -0,0 +1,125 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopKStream(t *testing.T) {
+	errRead := errors.New("read failed")
+
+	type row struct {
+		name   string
+		amount int
+	}
+
+	less := func(a, b row) bool {
+		return a.amount < b.amount
+	}
+
+	tests := []struct {
+		name        string
+		input       []row
+		err         error
+		k           int
+		expected    []row
+		expectedErr error
+	}{
+		{
+			name:     "Empty",
+			input:    []row{},
+			k:        3,
+			expected: nil,
+		},
+		{
+			name:     "Zero k",
+			input:    []row{{"a", 1}},
+			k:        0,
+			expected: nil,
+		},
+		{
+			name:     "Fewer items than k",
+			input:    []row{{"a", 1}, {"b", 3}},
+			k:        3,
+			expected: []row{{"b", 3}, {"a", 1}},
+		},
+		{
+			name: "Keeps the greatest",
+			input: []row{
+				{"a", 5}, {"b", 1}, {"c", 9}, {"d", 7}, {"e", 3}, {"f", 8},
+			},
+			k:        3,
+			expected: []row{{"c", 9}, {"f", 8}, {"d", 7}},
+		},
+		{
+			name: "Ties keep source order",
+			input: []row{
+				{"a", 5}, {"b", 5}, {"c", 1}, {"d", 5}, {"e", 6},
+			},
+			k:        3,
+			expected: []row{{"e", 6}, {"a", 5}, {"b", 5}},
+		},
+		{
+			name:        "Error yields nothing",
+			input:       []row{{"a", 1}},
+			err:         errRead,
+			k:           3,
+			expected:    nil,
+			expectedErr: errRead,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := TopK(MemReader(tc.input, tc.err), tc.k, less)
+
+			var got []row
+			for s.Next() {
+				got = append(got, s.Data())
+			}
+
+			assert.Equal(t, tc.expected, got)
+			assert.ErrorIs(t, s.Err(), tc.expectedErr)
+			assert.False(t, s.Next())
+			assert.NoError(t, s.Close())
+		})
+	}
+}
+
+func TestTopKStream_JSON(t *testing.T) {
+	s := TopK(jsonRows[int]("3\n9\n1\n7\n"), 2, func(a, b int) bool { return a < b })
+
+	result, err := Consume(s)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{9, 7}, result)
+	assert.NoError(t, s.Err())
+}
+
+// ExampleTopK demonstrates keeping the largest orders for a report.
+func ExampleTopK() {
+	type order struct {
+		ID     int
+		Amount float64
+	}
+
+	orders := MemReader([]order{
+		{1, 120}, {2, 35.5}, {3, 990}, {4, 410}, {5, 78},
+	}, nil)
+
+	stream := TopK(orders, 3, func(a, b order) bool {
+		return a.Amount < b.Amount
+	})
+	defer stream.Close()
+
+	for stream.Next() {
+		o := stream.Data()
+		fmt.Printf("#%d: %.2f\n", o.ID, o.Amount)
+	}
+	// Output:
+	// #3: 990.00
+	// #4: 410.00
+	// #1: 120.00
+}
@@ -0,0 +1,171 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+type (
+	// Violation describes why an item failed validation.
+	Violation struct {
+		// Line is the position of the item in the stream, starting at 1. It is
+		// set by Validate.
+		Line int
+		// Field locates the offending value, as a JSON pointer for JSON
+		// Schema or a dotted field path for struct rules. Empty when the item
+		// as a whole is invalid.
+		Field string
+		// Rule is the keyword or rule that failed, e.g. "required".
+		Rule    string
+		Message string
+	}
+
+	// Validator checks an item, returning its violations, if any.
+	Validator[T any] interface {
+		Validate(item T) []Violation
+	}
+
+	// ValidatorFunc adapts a function to a Validator.
+	ValidatorFunc[T any] func(item T) []Violation
+
+	// ValidationError is reported by a Validate stream stopped by an invalid
+	// item.
+	ValidationError struct {
+		Violations []Violation
+	}
+
+	// ValidateStream yields the items of the inner stream that pass a
+	// Validator.
+	ValidateStream[T any] struct {
+		inner     ReadStream[T]
+		validator Validator[T]
+		opts      validateOpts
+		line      int
+		current   T
+		err       error
+		done      bool
+	}
+)
+
+func (fn ValidatorFunc[T]) Validate(item T) []Violation {
+	return fn(item)
+}
+
+func (v Violation) String() string {
+	if v.Field == "" {
+		return fmt.Sprintf("line %d: %s: %s", v.Line, v.Rule, v.Message)
+	}
+	return fmt.Sprintf("line %d: %s: %s: %s", v.Line, v.Field, v.Rule, v.Message)
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.String()
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Validate creates a new ReadStream that yields the items of the inner
+// stream accepted by validator, such as a JSONSchema for json.RawMessage rows
+// or a StructValidator.
+//
+// The first invalid item stops the stream with a *ValidationError, unless a
+// rejects stream is given with WithValidateRejects, in which case its
+// violations are written there and valid items flow on.
+func Validate[T any](inner ReadStream[T], validator Validator[T], opts ...ValidateOpt) ReadStream[T] {
+	var optsDef validateOpts
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	return &ValidateStream[T]{
+		inner:     inner,
+		validator: validator,
+		opts:      optsDef,
+	}
+}
+
+func (s *ValidateStream[T]) Next() bool {
+	if s.done {
+		return false
+	}
+
+	for s.inner.Next() {
+		s.line++
+		item := s.inner.Data()
+
+		violations := s.validator.Validate(item)
+		if len(violations) == 0 {
+			s.current = item
+			return true
+		}
+
+		for i := range violations {
+			violations[i].Line = s.line + s.opts.lineOffset
+		}
+
+		if s.opts.rejects == nil {
+			return s.end(&ValidationError{Violations: violations})
+		}
+
+		for _, v := range violations {
+			if _, err := s.opts.rejects.Write(v); err != nil {
+				return s.end(fmt.Errorf("rejects: %w", err))
+			}
+		}
+	}
+
+	return s.end(nil)
+}
+
+// end stops the stream with err, flushing the rejects stream so the
+// violations written to it are not left in its buffers.
+func (s *ValidateStream[T]) end(err error) bool {
+	s.done = true
+	s.err = err
+
+	if s.opts.rejects != nil {
+		if flushErr := s.opts.rejects.Flush(); flushErr != nil && s.err == nil {
+			s.err = fmt.Errorf("rejects: %w", flushErr)
+		}
+	}
+
+	return false
+}
+
+func (s *ValidateStream[T]) Data() T {
+	return s.current
+}
+
+func (s *ValidateStream[T]) Err() error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.inner.Err()
+}
+
+// Close flushes the rejects stream, if the stream did not run to its end, and
+// closes the inner stream.
+func (s *ValidateStream[T]) Close() error {
+	var err error
+	if !s.done {
+		s.end(nil)
+		err = s.err
+	}
+
+	return errors.Join(err, s.inner.Close())
+}
+
+func (s *ValidateStream[T]) Iter() iter.Seq[T] {
+	return Iter(s)
+}
+
+func (s *ValidateStream[T]) Iter2() iter.Seq2[T, error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[any] = new(ValidateStream[any])
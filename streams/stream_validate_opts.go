@@ -0,0 +1,32 @@
+package streams
+
+type ValidateOpt func(*validateOpts)
+
+type validateOpts struct {
+	rejects    WriteStream[Violation]
+	lineOffset int
+}
+
+func (fn ValidateOpt) apply(o *validateOpts) {
+	fn(o)
+}
+
+// WithValidateRejects writes the violations of invalid items to w and carries
+// on with the next item, instead of stopping the stream. An error writing to
+// w stops the stream.
+//
+// w is flushed once the stream ends or is closed, but never closed: the caller
+// owns it and closes it once done.
+func WithValidateRejects(w WriteStream[Violation]) ValidateOpt {
+	return func(o *validateOpts) {
+		o.rejects = w
+	}
+}
+
+// WithValidateLineOffset adds offset to the line reported for every item,
+// e.g. 1 for a CSV file whose first line is a header.
+func WithValidateLineOffset(offset int) ValidateOpt {
+	return func(o *validateOpts) {
+		o.lineOffset = offset
+	}
+}
@@ -0,0 +1,164 @@
+package streams
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateStream(t *testing.T) {
+	positive := ValidatorFunc[int](func(x int) []Violation {
+		if x > 0 {
+			return nil
+		}
+		return []Violation{{Rule: "positive", Message: fmt.Sprintf("%d is not positive", x)}}
+	})
+
+	t.Run("Stops on the first invalid item", func(t *testing.T) {
+		s := Validate[int](MemReader([]int{1, -2, 3}, nil), positive)
+
+		assert.True(t, s.Next())
+		assert.False(t, s.Next())
+
+		var validationErr *ValidationError
+		require.ErrorAs(t, s.Err(), &validationErr)
+		assert.Equal(t, []Violation{{Line: 2, Rule: "positive", Message: "-2 is not positive"}}, validationErr.Violations)
+		assert.EqualError(t, s.Err(), "validation failed: line 2: positive: -2 is not positive")
+	})
+
+	t.Run("Routes rejects", func(t *testing.T) {
+		rejects := MemWriter[Violation]()
+
+		s := Validate[int](MemReader([]int{1, -2, 3, 0}, nil), positive,
+			WithValidateRejects(rejects),
+			WithValidateLineOffset(1),
+		)
+
+		result, err := Consume(s)
+		require.NoError(t, err)
+		assert.Equal(t, []int{1, 3}, result)
+		assert.Equal(t, []Violation{
+			{Line: 3, Rule: "positive", Message: "-2 is not positive"},
+			{Line: 5, Rule: "positive", Message: "0 is not positive"},
+		}, rejects.Items())
+	})
+
+	t.Run("Rejects failure stops the stream", func(t *testing.T) {
+		rejects := MemWriter[Violation]()
+		rejects.SetError(errors.New("full"))
+
+		s := Validate[int](MemReader([]int{-1, 2}, nil), positive, WithValidateRejects(rejects))
+		assert.False(t, s.Next())
+		assert.EqualError(t, s.Err(), "rejects: full")
+	})
+
+	t.Run("Rejects are flushed", func(t *testing.T) {
+		rejects := &flushCounter[Violation]{MemoryWriteStream: MemWriter[Violation]()}
+
+		s := Validate[int](MemReader([]int{-1, 2, -3}, nil), positive, WithValidateRejects(rejects))
+
+		require.True(t, s.Next())
+		assert.Zero(t, rejects.flushes)
+
+		assert.False(t, s.Next())
+		assert.False(t, s.Next())
+		assert.NoError(t, s.Err())
+		assert.Equal(t, 1, rejects.flushes)
+		assert.Len(t, rejects.Items(), 2)
+
+		require.NoError(t, s.Close())
+		assert.Equal(t, 1, rejects.flushes, "not flushed again on Close")
+
+		// Closed before the end
+		rejects = &flushCounter[Violation]{MemoryWriteStream: MemWriter[Violation]()}
+		s = Validate[int](MemReader([]int{-1, 2, -3}, nil), positive, WithValidateRejects(rejects))
+
+		require.True(t, s.Next())
+		require.NoError(t, s.Close())
+		assert.Equal(t, 1, rejects.flushes)
+		assert.False(t, s.Next())
+
+		// Flush errors are reported
+		rejects = &flushCounter[Violation]{
+			MemoryWriteStream: MemWriter[Violation](),
+			flushErr:          errors.New("disk full"),
+		}
+		s = Validate[int](MemReader([]int{-1}, nil), positive, WithValidateRejects(rejects))
+
+		assert.False(t, s.Next())
+		assert.EqualError(t, s.Err(), "rejects: disk full")
+	})
+
+	t.Run("Inner error", func(t *testing.T) {
+		boom := errors.New("boom")
+
+		s := Validate[int](MemReader([]int(nil), boom), positive)
+		assert.False(t, s.Next())
+		assert.ErrorIs(t, s.Err(), boom)
+	})
+}
+
+// ExampleValidate demonstrates validating JSONEachRow rows against a JSON Schema, routing bad rows to a rejects stream.
+func ExampleValidate() {
+	schema := MustJSONSchema([]byte(`{
+		"type": "object",
+		"required": ["id", "amount"],
+		"properties": {
+			"id": {"type": "integer"},
+			"amount": {"type": "number", "minimum": 0}
+		}
+	}`))
+
+	input := `{"id": 1, "amount": 10.5}
+{"id": 2, "amount": -3}
+{"id": "3"}
+{"id": 4, "amount": 0}`
+
+	rows := JSON[json.RawMessage](io.NopCloser(strings.NewReader(input)))
+	rejects := MemWriter[Violation]()
+
+	valid, _ := Consume(Validate(rows, schema, WithValidateRejects(rejects)))
+	fmt.Println("valid rows:", len(valid))
+
+	for _, v := range rejects.Items() {
+		fmt.Println(v)
+	}
+	// Output:
+	// valid rows: 2
+	// line 2: /amount: minimum: must be >= 0
+	// line 3: /amount: required: is required
+	// line 3: /id: type: must be of type integer, got string
+}
+
+// ExampleNewStructValidator demonstrates validating CSV rows with struct tag rules.
+func ExampleNewStructValidator() {
+	type signup struct {
+		Email string `csv:"email" validate:"required,email"`
+		Age   int    `csv:"age" validate:"min=18"`
+	}
+
+	input := "email,age\nada@example.com,36\nnot-an-email,17\n"
+
+	rows, _ := CSV[signup](WithCSVReader(io.NopCloser(strings.NewReader(input))), WithCSVHeader())
+	rejects := MemWriter[Violation]()
+
+	valid, _ := Consume(Validate(rows, MustStructValidator[signup](),
+		WithValidateRejects(rejects),
+		WithValidateLineOffset(1), // header
+	))
+
+	fmt.Println(valid)
+	for _, v := range rejects.Items() {
+		fmt.Println(v)
+	}
+	// Output:
+	// [{ada@example.com 36}]
+	// line 3: Email: email: must be an email address
+	// line 3: Age: min: must be >= 18
+}
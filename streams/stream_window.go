@@ -0,0 +1,293 @@
+package streams
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"iter"
+	"time"
+)
+
+var ErrWindowSize = errors.New("window size and slide must be positive")
+
+type (
+	// Window is a group of items sharing a key whose event times fall in
+	// the half-open interval [Start, End).
+	Window[K comparable, T any] struct {
+		Key   K
+		Start time.Time
+		End   time.Time
+		Items []T
+	}
+
+	// WatermarkStrategy derives the watermark from the event times seen so
+	// far. The watermark asserts that no more records older than it are
+	// expected; windows ending before it can be emitted.
+	WatermarkStrategy interface {
+		// Observe is called with the event time of every record and returns
+		// the current watermark.
+		Observe(ts time.Time) time.Time
+	}
+
+	boundedOutOfOrderness struct {
+		delay time.Duration
+		max   time.Time
+	}
+
+	windowID[K comparable] struct {
+		key   K
+		start int64
+	}
+
+	windowState[K comparable, T any] struct {
+		window Window[K, T]
+		seq    uint64
+	}
+
+	windowHeap[K comparable, T any] []*windowState[K, T]
+
+	// WindowStream assigns items to event-time windows per key and emits each
+	// window once the watermark has passed its end plus the allowed lateness.
+	// Remaining windows are emitted when the inner stream ends.
+	WindowStream[T any, K comparable] struct {
+		inner     ReadStream[T]
+		keyFunc   func(T) K
+		tsFunc    func(T) time.Time
+		size      time.Duration
+		slide     time.Duration
+		opts      windowOpts[T]
+		open      map[windowID[K]]*windowState[K, T]
+		queue     windowHeap[K, T]
+		ready     []Window[K, T]
+		current   Window[K, T]
+		watermark time.Time
+		seq       uint64
+		err       error
+		done      bool
+	}
+)
+
+// BoundedOutOfOrderness returns a WatermarkStrategy that tolerates records
+// arriving up to `delay` later than the greatest event time seen so far.
+func BoundedOutOfOrderness(delay time.Duration) WatermarkStrategy {
+	return &boundedOutOfOrderness{delay: delay}
+}
+
+// AscendingTimestamps returns a WatermarkStrategy for sources whose event
+// times never go backwards.
+func AscendingTimestamps() WatermarkStrategy {
+	return BoundedOutOfOrderness(0)
+}
+
+func (w *boundedOutOfOrderness) Observe(ts time.Time) time.Time {
+	if ts.After(w.max) {
+		w.max = ts
+	}
+	return w.max.Add(-w.delay)
+}
+
+// TumblingWindow creates a new stream that groups items by key into fixed,
+// non-overlapping windows of `size` based on the event time returned by
+// tsFunc. Windows are aligned to the Unix epoch.
+//
+// Unlike Group, items sharing a key do not need to be consecutive, and out of
+// order records are attributed to the right window as long as they are within
+// the watermark delay and the allowed lateness.
+//
+// A non-positive size yields no window and makes Err report ErrWindowSize.
+func TumblingWindow[T any, K comparable](
+	inner ReadStream[T],
+	keyFunc func(T) K,
+	tsFunc func(T) time.Time,
+	size time.Duration,
+	opts ...WindowOpt[T],
+) ReadStream[Window[K, T]] {
+	return SlidingWindow(inner, keyFunc, tsFunc, size, size, opts...)
+}
+
+// SlidingWindow creates a new stream that groups items by key into windows
+// of `size` starting every `slide`. When slide is smaller than size, windows
+// overlap and a single item belongs to several of them.
+//
+// Both size and slide must be positive, otherwise the stream yields no window
+// and Err reports ErrWindowSize.
+func SlidingWindow[T any, K comparable](
+	inner ReadStream[T],
+	keyFunc func(T) K,
+	tsFunc func(T) time.Time,
+	size time.Duration,
+	slide time.Duration,
+	opts ...WindowOpt[T],
+) ReadStream[Window[K, T]] {
+	if size <= 0 || slide <= 0 {
+		return &WindowStream[T, K]{
+			inner: inner,
+			err:   fmt.Errorf("%w: size %s, slide %s", ErrWindowSize, size, slide),
+			done:  true,
+		}
+	}
+
+	optsDef := windowOpts[T]{
+		watermark: AscendingTimestamps(),
+	}
+
+	for _, opt := range opts {
+		opt.apply(&optsDef)
+	}
+
+	return &WindowStream[T, K]{
+		inner:   inner,
+		keyFunc: keyFunc,
+		tsFunc:  tsFunc,
+		size:    size,
+		slide:   slide,
+		opts:    optsDef,
+		open:    make(map[windowID[K]]*windowState[K, T]),
+	}
+}
+
+func (s *WindowStream[T, K]) Next() bool {
+	for {
+		if len(s.ready) > 0 {
+			s.current = s.ready[0]
+			s.ready = s.ready[1:]
+			return true
+		}
+
+		if s.done {
+			return false
+		}
+
+		if !s.inner.Next() {
+			if err := s.inner.Err(); err != nil {
+				s.err = err
+			}
+			s.done = true
+			s.fire(func(*windowState[K, T]) bool { return true })
+			continue
+		}
+
+		s.add(s.inner.Data())
+	}
+}
+
+func (s *WindowStream[T, K]) add(item T) {
+	ts := s.tsFunc(item)
+
+	if wm := s.opts.watermark.Observe(ts); wm.After(s.watermark) {
+		s.watermark = wm
+	}
+
+	var (
+		key      = s.keyFunc(item)
+		assigned bool
+		nanos    = ts.UnixNano()
+		last     = nanos - mod(nanos, int64(s.slide))
+	)
+
+	for start := last; start > nanos-int64(s.size); start -= int64(s.slide) {
+		end := time.Unix(0, start).Add(s.size)
+		if s.expired(end) {
+			// Older windows are expired as well
+			break
+		}
+
+		id := windowID[K]{key: key, start: start}
+		state, ok := s.open[id]
+		if !ok {
+			state = &windowState[K, T]{
+				window: Window[K, T]{
+					Key:   key,
+					Start: time.Unix(0, start).In(ts.Location()),
+					End:   end.In(ts.Location()),
+				},
+				seq: s.seq,
+			}
+			s.seq++
+			s.open[id] = state
+			heap.Push(&s.queue, state)
+		}
+
+		state.window.Items = append(state.window.Items, item)
+		assigned = true
+	}
+
+	if !assigned && s.opts.lateFn != nil {
+		s.opts.lateFn(item)
+	}
+
+	s.fire(func(state *windowState[K, T]) bool {
+		return s.expired(state.window.End)
+	})
+}
+
+// expired reports whether a window ending at `end` may no longer receive
+// records given the current watermark.
+func (s *WindowStream[T, K]) expired(end time.Time) bool {
+	return !end.Add(s.opts.lateness).After(s.watermark)
+}
+
+// fire moves the windows matching the predicate to the ready queue, in
+// order of end time and then creation.
+func (s *WindowStream[T, K]) fire(predicate func(*windowState[K, T]) bool) {
+	for len(s.queue) > 0 && predicate(s.queue[0]) {
+		state := heap.Pop(&s.queue).(*windowState[K, T])
+		delete(s.open, windowID[K]{key: state.window.Key, start: state.window.Start.UnixNano()})
+		s.ready = append(s.ready, state.window)
+	}
+}
+
+func (s *WindowStream[T, K]) Data() Window[K, T] {
+	return s.current
+}
+
+func (s *WindowStream[T, K]) Err() error {
+	return s.err
+}
+
+func (s *WindowStream[T, K]) Close() error {
+	return s.inner.Close()
+}
+
+func (s *WindowStream[T, K]) Iter() iter.Seq[Window[K, T]] {
+	return Iter(s)
+}
+
+func (h windowHeap[K, T]) Len() int {
+	return len(h)
+}
+
+func (h windowHeap[K, T]) Less(i, j int) bool {
+	if !h[i].window.End.Equal(h[j].window.End) {
+		return h[i].window.End.Before(h[j].window.End)
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h windowHeap[K, T]) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+}
+
+func (h *windowHeap[K, T]) Push(x any) {
+	*h = append(*h, x.(*windowState[K, T]))
+}
+
+func (h *windowHeap[K, T]) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return x
+}
+
+// mod returns the non-negative remainder of a divided by b.
+func mod(a, b int64) int64 {
+	m := a % b
+	if m < 0 {
+		m += b
+	}
+	return m
+}
+
+var _ ReadStream[Window[string, any]] = new(WindowStream[any, string])
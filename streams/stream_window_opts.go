@@ -0,0 +1,40 @@
+package streams
+
+import "time"
+
+type WindowOpt[T any] func(*windowOpts[T])
+
+type windowOpts[T any] struct {
+	watermark WatermarkStrategy
+	lateness  time.Duration
+	lateFn    func(T)
+}
+
+func (fn WindowOpt[T]) apply(o *windowOpts[T]) {
+	fn(o)
+}
+
+// WithWindowWatermark sets the strategy used to derive the watermark from
+// the observed event times. Defaults to AscendingTimestamps.
+func WithWindowWatermark[T any](strategy WatermarkStrategy) WindowOpt[T] {
+	return func(o *windowOpts[T]) {
+		o.watermark = strategy
+	}
+}
+
+// WithWindowAllowedLateness keeps windows open for `lateness` after the
+// watermark has passed their end, so records arriving late are still
+// attributed to them.
+func WithWindowAllowedLateness[T any](lateness time.Duration) WindowOpt[T] {
+	return func(o *windowOpts[T]) {
+		o.lateness = lateness
+	}
+}
+
+// WithWindowLateFunc sets a callback receiving the records that arrived after
+// every window they belong to was emitted. Such records are dropped otherwise.
+func WithWindowLateFunc[T any](fn func(T)) WindowOpt[T] {
+	return func(o *windowOpts[T]) {
+		o.lateFn = fn
+	}
+}
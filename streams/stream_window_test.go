@@ -0,0 +1,263 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type windowEvent struct {
+	Key string
+	At  time.Time
+	Val int
+}
+
+var windowEpoch = time.Date(2025, 12, 8, 12, 0, 0, 0, time.UTC)
+
+func windowAt(key string, seconds, val int) windowEvent {
+	return windowEvent{Key: key, At: windowEpoch.Add(time.Duration(seconds) * time.Second), Val: val}
+}
+
+func windowValues(w Window[string, windowEvent]) []int {
+	res := make([]int, 0, len(w.Items))
+	for _, item := range w.Items {
+		res = append(res, item.Val)
+	}
+	return res
+}
+
+func windowEventKey(e windowEvent) string { return e.Key }
+
+func windowEventTime(e windowEvent) time.Time { return e.At }
+
+func TestTumblingWindow(t *testing.T) {
+	type expected struct {
+		key    string
+		start  int
+		values []int
+	}
+
+	tests := []struct {
+		name     string
+		events   []windowEvent
+		opts     []WindowOpt[windowEvent]
+		expected []expected
+		late     []int
+	}{
+		{
+			name:     "Empty stream",
+			events:   []windowEvent{},
+			expected: []expected{},
+		},
+		{
+			name: "Ordered events per key",
+			events: []windowEvent{
+				windowAt("a", 0, 1),
+				windowAt("b", 1, 2),
+				windowAt("a", 9, 3),
+				windowAt("a", 10, 4),
+				windowAt("b", 25, 5),
+			},
+			expected: []expected{
+				{key: "a", start: 0, values: []int{1, 3}},
+				{key: "b", start: 0, values: []int{2}},
+				{key: "a", start: 10, values: []int{4}},
+				{key: "b", start: 20, values: []int{5}},
+			},
+		},
+		{
+			name: "Out of order events are dropped without watermark delay",
+			events: []windowEvent{
+				windowAt("a", 1, 1),
+				windowAt("a", 12, 2),
+				windowAt("a", 5, 3),
+			},
+			expected: []expected{
+				{key: "a", start: 0, values: []int{1}},
+				{key: "a", start: 10, values: []int{2}},
+			},
+			late: []int{3},
+		},
+		{
+			name: "Out of order events within bounded watermark",
+			events: []windowEvent{
+				windowAt("a", 1, 1),
+				windowAt("a", 12, 2),
+				windowAt("a", 5, 3),
+				windowAt("a", 16, 4),
+				windowAt("a", 9, 5),
+			},
+			opts: []WindowOpt[windowEvent]{WithWindowWatermark[windowEvent](BoundedOutOfOrderness(5 * time.Second))},
+			expected: []expected{
+				{key: "a", start: 0, values: []int{1, 3}},
+				{key: "a", start: 10, values: []int{2, 4}},
+			},
+			late: []int{5},
+		},
+		{
+			name: "Allowed lateness keeps windows open",
+			events: []windowEvent{
+				windowAt("a", 1, 1),
+				windowAt("a", 12, 2),
+				windowAt("a", 5, 3),
+				windowAt("a", 21, 4),
+				windowAt("a", 6, 5),
+			},
+			opts: []WindowOpt[windowEvent]{WithWindowAllowedLateness[windowEvent](5 * time.Second)},
+			expected: []expected{
+				{key: "a", start: 0, values: []int{1, 3}},
+				{key: "a", start: 10, values: []int{2}},
+				{key: "a", start: 20, values: []int{4}},
+			},
+			late: []int{5},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var late []int
+
+			opts := append(tc.opts, WithWindowLateFunc(func(e windowEvent) {
+				late = append(late, e.Val)
+			}))
+
+			stream := TumblingWindow(
+				MemReader(tc.events, nil),
+				windowEventKey,
+				windowEventTime,
+				10*time.Second,
+				opts...,
+			)
+
+			got := make([]expected, 0)
+			for stream.Next() {
+				w := stream.Data()
+				assert.Equal(t, 10*time.Second, w.End.Sub(w.Start))
+				got = append(got, expected{
+					key:    w.Key,
+					start:  int(w.Start.Sub(windowEpoch) / time.Second),
+					values: windowValues(w),
+				})
+			}
+
+			assert.NoError(t, stream.Err())
+			assert.Equal(t, tc.expected, got)
+			assert.Equal(t, tc.late, late)
+		})
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	stream := SlidingWindow(
+		MemReader([]windowEvent{
+			windowAt("a", 1, 1),
+			windowAt("a", 6, 2),
+			windowAt("a", 11, 3),
+		}, nil),
+		windowEventKey,
+		windowEventTime,
+		10*time.Second,
+		5*time.Second,
+	)
+
+	var got [][]int
+	var starts []int
+	for stream.Next() {
+		w := stream.Data()
+		got = append(got, windowValues(w))
+		starts = append(starts, int(w.Start.Sub(windowEpoch)/time.Second))
+	}
+
+	assert.NoError(t, stream.Err())
+	assert.Equal(t, []int{-5, 0, 5, 10}, starts)
+	assert.Equal(t, [][]int{{1}, {1, 2}, {2, 3}, {3}}, got)
+}
+
+func TestWindowStream_Error(t *testing.T) {
+	errStream := errors.New("stream error")
+
+	stream := TumblingWindow(
+		MemReader([]windowEvent{windowAt("a", 1, 1)}, errStream),
+		windowEventKey,
+		windowEventTime,
+		time.Minute,
+	)
+
+	result, err := Consume(stream)
+	assert.ErrorIs(t, err, errStream)
+	assert.Nil(t, result)
+}
+
+func TestWindowStream_InvalidSize(t *testing.T) {
+	tests := []struct {
+		name  string
+		size  time.Duration
+		slide time.Duration
+	}{
+		{name: "Zero size", size: 0, slide: time.Second},
+		{name: "Negative size", size: -time.Second, slide: time.Second},
+		{name: "Zero slide", size: time.Second, slide: 0},
+		{name: "Negative slide", size: time.Second, slide: -time.Second},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inner := track(MemReader([]windowEvent{windowAt("a", 1, 1)}, nil))
+
+			stream := SlidingWindow[windowEvent](inner, windowEventKey, windowEventTime, tc.size, tc.slide)
+
+			assert.False(t, stream.Next())
+			assert.ErrorIs(t, stream.Err(), ErrWindowSize)
+			assert.NoError(t, stream.Close())
+			assert.True(t, inner.closed)
+		})
+	}
+
+	t.Run("TumblingWindow", func(t *testing.T) {
+		stream := TumblingWindow(MemReader([]windowEvent{windowAt("a", 1, 1)}, nil), windowEventKey, windowEventTime, 0)
+
+		result, err := Consume(stream)
+		assert.ErrorIs(t, err, ErrWindowSize)
+		assert.Empty(t, result)
+	})
+}
+
+// ExampleTumblingWindow demonstrates grouping events by key into fixed time windows.
+func ExampleTumblingWindow() {
+	type trade struct {
+		Symbol string
+		At     time.Time
+		Amount int
+	}
+
+	base := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+	trades := []trade{
+		{"BTC", base.Add(10 * time.Second), 1},
+		{"ETH", base.Add(20 * time.Second), 2},
+		{"BTC", base.Add(50 * time.Second), 3},
+		{"BTC", base.Add(70 * time.Second), 4},
+	}
+
+	windows := TumblingWindow(
+		MemReader(trades, nil),
+		func(t trade) string { return t.Symbol },
+		func(t trade) time.Time { return t.At },
+		time.Minute,
+	)
+
+	for windows.Next() {
+		w := windows.Data()
+		total := 0
+		for _, t := range w.Items {
+			total += t.Amount
+		}
+		fmt.Printf("%s [%s, %s): %d\n", w.Key, w.Start.Format("15:04"), w.End.Format("15:04"), total)
+	}
+	// Output:
+	// BTC [10:00, 10:01): 4
+	// ETH [10:00, 10:01): 2
+	// BTC [10:01, 10:02): 4
+}
@@ -0,0 +1,76 @@
+package streams
+
+import (
+	"errors"
+	"io"
+	"iter"
+
+	"github.com/sonirico/vago/tuples"
+)
+
+// ZipStream pairs the items of two streams by position.
+type ZipStream[A, B any] struct {
+	a       ReadStream[A]
+	b       ReadStream[B]
+	current tuples.Tuple2[A, B]
+	err     error
+	done    bool
+}
+
+// Zip creates a new ReadStream that yields a tuple with the next item of each
+// stream, stopping as soon as either of them is exhausted. An error in any of
+// the streams, other than io.EOF, stops the stream and is reported by Err.
+// Close closes both streams.
+func Zip[A, B any](a ReadStream[A], b ReadStream[B]) ReadStream[tuples.Tuple2[A, B]] {
+	return &ZipStream[A, B]{
+		a: a,
+		b: b,
+	}
+}
+
+func (s *ZipStream[A, B]) Next() bool {
+	if s.done {
+		return false
+	}
+
+	if !s.a.Next() {
+		return s.end(s.a.Err())
+	}
+
+	if !s.b.Next() {
+		return s.end(s.b.Err())
+	}
+
+	s.current = tuples.Tuple2[A, B]{V1: s.a.Data(), V2: s.b.Data()}
+	return true
+}
+
+func (s *ZipStream[A, B]) end(err error) bool {
+	s.done = true
+	if !errors.Is(err, io.EOF) {
+		s.err = err
+	}
+	return false
+}
+
+func (s *ZipStream[A, B]) Data() tuples.Tuple2[A, B] {
+	return s.current
+}
+
+func (s *ZipStream[A, B]) Err() error {
+	return s.err
+}
+
+func (s *ZipStream[A, B]) Close() error {
+	return errors.Join(s.a.Close(), s.b.Close())
+}
+
+func (s *ZipStream[A, B]) Iter() iter.Seq[tuples.Tuple2[A, B]] {
+	return Iter(s)
+}
+
+func (s *ZipStream[A, B]) Iter2() iter.Seq2[tuples.Tuple2[A, B], error] {
+	return Iter2(s)
+}
+
+var _ ReadStream[tuples.Tuple2[any, any]] = new(ZipStream[any, any])
@@ -0,0 +1,108 @@
+package streams
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sonirico/vago/tuples"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZipStream(t *testing.T) {
+	errRead := errors.New("read failed")
+
+	tests := []struct {
+		name        string
+		a           ReadStream[int]
+		b           ReadStream[string]
+		expected    []tuples.Tuple2[int, string]
+		expectedErr error
+	}{
+		{
+			name: "Same length",
+			a:    MemReader([]int{1, 2}, nil),
+			b:    MemReader([]string{"a", "b"}, nil),
+			expected: []tuples.Tuple2[int, string]{
+				{V1: 1, V2: "a"},
+				{V1: 2, V2: "b"},
+			},
+		},
+		{
+			name: "Shortest wins",
+			a:    MemReader([]int{1, 2, 3}, nil),
+			b:    MemReader([]string{"a"}, nil),
+			expected: []tuples.Tuple2[int, string]{
+				{V1: 1, V2: "a"},
+			},
+		},
+		{
+			name:     "Empty",
+			a:        MemReader([]int{}, nil),
+			b:        MemReader([]string{"a"}, nil),
+			expected: nil,
+		},
+		{
+			name: "Error on the right",
+			a:    MemReader([]int{1, 2}, nil),
+			b:    MemReader([]string{"a"}, errRead),
+			expected: []tuples.Tuple2[int, string]{
+				{V1: 1, V2: "a"},
+			},
+			expectedErr: errRead,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Zip(tc.a, tc.b)
+
+			var got []tuples.Tuple2[int, string]
+			for s.Next() {
+				got = append(got, s.Data())
+			}
+
+			assert.Equal(t, tc.expected, got)
+			assert.ErrorIs(t, s.Err(), tc.expectedErr)
+			assert.False(t, s.Next())
+		})
+	}
+}
+
+func TestZipStream_JSON(t *testing.T) {
+	s := Zip(jsonRows[int]("1\n2\n3\n"), jsonRows[string]("\"a\"\n\"b\"\n"))
+
+	var got []tuples.Tuple2[int, string]
+	for s.Next() {
+		got = append(got, s.Data())
+	}
+
+	assert.Equal(t, []tuples.Tuple2[int, string]{{V1: 1, V2: "a"}, {V1: 2, V2: "b"}}, got)
+	assert.NoError(t, s.Err())
+}
+
+func TestZipStream_Close(t *testing.T) {
+	a := track[int](MemReader([]int{1}, nil))
+	b := track[int](MemReader([]int{2}, nil))
+
+	assert.NoError(t, Zip[int, int](a, b).Close())
+	assert.True(t, a.closed)
+	assert.True(t, b.closed)
+}
+
+// ExampleZip demonstrates pairing two streams by position.
+func ExampleZip() {
+	names := MemReader([]string{"Alice", "Bob"}, nil)
+	scores := MemReader([]int{90, 75}, nil)
+
+	stream := Zip[string, int](names, scores)
+	defer stream.Close()
+
+	for stream.Next() {
+		pair := stream.Data()
+		fmt.Printf("%s: %d\n", pair.V1, pair.V2)
+	}
+	// Output:
+	// Alice: 90
+	// Bob: 75
+}
@@ -99,6 +99,11 @@ func WriteSeqValues[K, V any](stream WriteStream[V], items iter.Seq2[K, V]) (int
 
 // Pipe copies all items from a ReadStream to a WriteStream
 // Returns the total number of bytes written and any error
+//
+// Once src is exhausted its Err is checked, so a stream stopped early, for
+// instance by WithContext, makes Pipe fail with "read error" rather than
+// reporting a partial copy as a success. io.EOF counts as a clean end. dst is
+// not flushed in that case.
 func Pipe[T any](src ReadStream[T], dst WriteStream[T]) (int64, error) {
 	var totalBytes int64
 
@@ -117,6 +122,10 @@ func Pipe[T any](src ReadStream[T], dst WriteStream[T]) (int64, error) {
 		totalBytes += n
 	}
 
+	if err := src.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return totalBytes, fmt.Errorf("read error: %w", err)
+	}
+
 	if err := dst.Flush(); err != nil {
 		return totalBytes, fmt.Errorf("flush error: %w", err)
 	}
@@ -126,6 +135,9 @@ func Pipe[T any](src ReadStream[T], dst WriteStream[T]) (int64, error) {
 
 // Multicast copies all items from a ReadStream to multiple WriteStreams
 // Returns a slice with bytes written to each destination and any error
+//
+// Like Pipe, it fails with "read error" when src stops with an error other
+// than io.EOF, without flushing the destinations.
 func Multicast[T any](src ReadStream[T], destinations ...WriteStream[T]) ([]int64, error) {
 	if len(destinations) == 0 {
 		return []int64{}, nil
@@ -151,6 +163,10 @@ func Multicast[T any](src ReadStream[T], destinations ...WriteStream[T]) ([]int6
 		}
 	}
 
+	if err := src.Err(); err != nil && !errors.Is(err, io.EOF) {
+		return bytesWritten, fmt.Errorf("read error: %w", err)
+	}
+
 	for i, dst := range destinations {
 		if err := dst.Flush(); err != nil {
 			return bytesWritten, fmt.Errorf("flush error for destination %d: %w", i, err)
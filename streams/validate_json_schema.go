@@ -0,0 +1,814 @@
+package streams
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// JSONSchema validates JSON documents against a JSON Schema (draft
+	// 2020-12).
+	//
+	// Only a subset of the specification is implemented:
+	//   - type, enum and const
+	//   - minimum, maximum, exclusiveMinimum, exclusiveMaximum and multipleOf
+	//   - minLength, maxLength and pattern
+	//   - prefixItems, items, contains, minContains, maxContains, minItems,
+	//     maxItems and uniqueItems
+	//   - properties, patternProperties, additionalProperties,
+	//     propertyNames, required, dependentRequired, minProperties and
+	//     maxProperties
+	//   - allOf, anyOf, oneOf, not and if/then/else
+	//   - $defs, and $ref to local JSON pointers such as "#/$defs/address"
+	//
+	// Schemas using keywords that would change the outcome of validation
+	// but are not implemented, such as unevaluatedProperties,
+	// dependentSchemas, $anchor or $dynamicRef, are rejected by
+	// NewJSONSchema rather than silently ignored; so is $id anywhere but
+	// at the root. Annotations such as title, description or format are
+	// ignored.
+	JSONSchema struct {
+		root *jsonSchemaNode
+	}
+
+	jsonSchemaNode struct {
+		// always is set for the boolean schemas true and false.
+		always *bool
+
+		ref     string
+		refNode *jsonSchemaNode
+
+		types    []string
+		enum     []any
+		constant any
+		hasConst bool
+
+		minimum, maximum, exclusiveMinimum, exclusiveMaximum, multipleOf *big.Rat
+
+		minLength, maxLength *int
+		pattern              *regexp.Regexp
+
+		prefixItems              []*jsonSchemaNode
+		items, contains          *jsonSchemaNode
+		minItems, maxItems       *int
+		minContains, maxContains *int
+		uniqueItems              bool
+
+		properties           map[string]*jsonSchemaNode
+		patternProperties    []jsonSchemaPattern
+		additionalProperties *jsonSchemaNode
+		propertyNames        *jsonSchemaNode
+		required             []string
+		dependentRequired    map[string][]string
+		minProps, maxProps   *int
+
+		allOf, anyOf, oneOf []*jsonSchemaNode
+		not                 *jsonSchemaNode
+		ifNode              *jsonSchemaNode
+		thenNode, elseNode  *jsonSchemaNode
+	}
+
+	jsonSchemaPattern struct {
+		re   *regexp.Regexp
+		node *jsonSchemaNode
+	}
+
+	// jsonSchemaCompiler compiles a schema document, resolving references
+	// against its root.
+	jsonSchemaCompiler struct {
+		doc   any
+		nodes map[string]*jsonSchemaNode
+		refs  []*jsonSchemaNode
+	}
+)
+
+// jsonSchemaUnsupported lists the keywords that affect validation but are not
+// implemented, including the draft 7 ones replaced in draft 2020-12.
+var jsonSchemaUnsupported = []string{
+	"unevaluatedProperties",
+	"unevaluatedItems",
+	"dependentSchemas",
+	"$anchor",
+	"$dynamicRef",
+	"$dynamicAnchor",
+	"$recursiveRef",
+	"$recursiveAnchor",
+	"dependencies",
+	"additionalItems",
+}
+
+// NewJSONSchema compiles a JSON Schema, returning an error if it is not valid
+// JSON, uses malformed or unsupported keywords or references outside the
+// document.
+func NewJSONSchema(schema []byte) (*JSONSchema, error) {
+	doc, err := decodeJSONNumbers(schema)
+	if err != nil {
+		return nil, fmt.Errorf("json schema: %w", err)
+	}
+
+	c := &jsonSchemaCompiler{doc: doc, nodes: make(map[string]*jsonSchemaNode)}
+
+	root, err := c.compile(doc, "#")
+	if err != nil {
+		return nil, fmt.Errorf("json schema: %w", err)
+	}
+
+	// References are resolved once every node exists, so that recursive
+	// schemas point to themselves
+	for i := 0; i < len(c.refs); i++ {
+		node := c.refs[i]
+		if node.refNode, err = c.resolve(node.ref); err != nil {
+			return nil, fmt.Errorf("json schema: %w", err)
+		}
+	}
+
+	return &JSONSchema{root: root}, nil
+}
+
+// MustJSONSchema is like NewJSONSchema but panics on error, for schemas known
+// at compile time.
+func MustJSONSchema(schema []byte) *JSONSchema {
+	s, err := NewJSONSchema(schema)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// Validate checks a JSON document, e.g. a row read with JSON[json.RawMessage].
+// Fields are reported as JSON pointers, such as "/items/0/price".
+func (s *JSONSchema) Validate(item json.RawMessage) []Violation {
+	doc, err := decodeJSONNumbers(item)
+	if err != nil {
+		return []Violation{{Rule: "json", Message: err.Error()}}
+	}
+
+	var violations []Violation
+	s.root.validate(doc, "", &violations)
+	return violations
+}
+
+func decodeJSONNumbers(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	if dec.More() {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+
+	return doc, nil
+}
+
+func (c *jsonSchemaCompiler) compile(v any, location string) (*jsonSchemaNode, error) {
+	if node, ok := c.nodes[location]; ok {
+		return node, nil
+	}
+
+	node := new(jsonSchemaNode)
+	c.nodes[location] = node
+
+	switch schema := v.(type) {
+	case bool:
+		node.always = &schema
+		return node, nil
+	case map[string]any:
+		return node, c.compileObject(node, schema, location)
+	default:
+		return nil, fmt.Errorf("%s: schema must be an object or a boolean", location)
+	}
+}
+
+func (c *jsonSchemaCompiler) compileObject(node *jsonSchemaNode, schema map[string]any, location string) error {
+	var err error
+
+	for _, key := range jsonSchemaUnsupported {
+		if _, ok := schema[key]; ok {
+			return fmt.Errorf("%s: unsupported keyword %q", location, key)
+		}
+	}
+
+	// A nested $id would change the base for resolving references
+	if _, ok := schema["$id"]; ok && location != "#" {
+		return fmt.Errorf("%s: unsupported keyword \"$id\" outside the root schema", location)
+	}
+
+	sub := func(key string) (*jsonSchemaNode, error) {
+		v, ok := schema[key]
+		if !ok {
+			return nil, nil
+		}
+		return c.compile(v, location+"/"+escapeJSONPointer(key))
+	}
+
+	list := func(key string) ([]*jsonSchemaNode, error) {
+		v, ok := schema[key]
+		if !ok {
+			return nil, nil
+		}
+
+		items, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/%s: must be an array", location, key)
+		}
+
+		nodes := make([]*jsonSchemaNode, len(items))
+		for i, item := range items {
+			if nodes[i], err = c.compile(item, fmt.Sprintf("%s/%s/%d", location, key, i)); err != nil {
+				return nil, err
+			}
+		}
+		return nodes, nil
+	}
+
+	number := func(key string) (*big.Rat, error) {
+		v, ok := schema[key]
+		if !ok {
+			return nil, nil
+		}
+
+		n, ok := v.(json.Number)
+		if !ok {
+			return nil, fmt.Errorf("%s/%s: must be a number", location, key)
+		}
+		return jsonRat(n), nil
+	}
+
+	count := func(key string) (*int, error) {
+		r, err := number(key)
+		if err != nil || r == nil {
+			return nil, err
+		}
+
+		if !r.IsInt() || r.Sign() < 0 {
+			return nil, fmt.Errorf("%s/%s: must be a non-negative integer", location, key)
+		}
+
+		n := int(r.Num().Int64())
+		return &n, nil
+	}
+
+	pattern := func(key, expr string) (*regexp.Regexp, error) {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("%s/%s: %w", location, key, err)
+		}
+		return re, nil
+	}
+
+	if ref, ok := schema["$ref"].(string); ok {
+		node.ref = ref
+		c.refs = append(c.refs, node)
+	}
+
+	if defs, ok := schema["$defs"].(map[string]any); ok {
+		for name, def := range defs {
+			if _, err = c.compile(def, location+"/$defs/"+escapeJSONPointer(name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	switch t := schema["type"].(type) {
+	case nil:
+	case string:
+		node.types = []string{t}
+	case []any:
+		for _, item := range t {
+			name, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("%s/type: must be a string or an array of strings", location)
+			}
+			node.types = append(node.types, name)
+		}
+	default:
+		return fmt.Errorf("%s/type: must be a string or an array of strings", location)
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		if node.enum, ok = enum.([]any); !ok {
+			return fmt.Errorf("%s/enum: must be an array", location)
+		}
+	}
+
+	node.constant, node.hasConst = schema["const"]
+
+	if node.minimum, err = number("minimum"); err != nil {
+		return err
+	}
+	if node.maximum, err = number("maximum"); err != nil {
+		return err
+	}
+	if node.exclusiveMinimum, err = number("exclusiveMinimum"); err != nil {
+		return err
+	}
+	if node.exclusiveMaximum, err = number("exclusiveMaximum"); err != nil {
+		return err
+	}
+	if node.multipleOf, err = number("multipleOf"); err != nil {
+		return err
+	}
+	if node.multipleOf != nil && node.multipleOf.Sign() <= 0 {
+		return fmt.Errorf("%s/multipleOf: must be greater than 0", location)
+	}
+
+	if node.minLength, err = count("minLength"); err != nil {
+		return err
+	}
+	if node.maxLength, err = count("maxLength"); err != nil {
+		return err
+	}
+	if expr, ok := schema["pattern"].(string); ok {
+		if node.pattern, err = pattern("pattern", expr); err != nil {
+			return err
+		}
+	}
+
+	if node.prefixItems, err = list("prefixItems"); err != nil {
+		return err
+	}
+	if node.items, err = sub("items"); err != nil {
+		return err
+	}
+	if node.contains, err = sub("contains"); err != nil {
+		return err
+	}
+	if node.minItems, err = count("minItems"); err != nil {
+		return err
+	}
+	if node.maxItems, err = count("maxItems"); err != nil {
+		return err
+	}
+	if node.minContains, err = count("minContains"); err != nil {
+		return err
+	}
+	if node.maxContains, err = count("maxContains"); err != nil {
+		return err
+	}
+	node.uniqueItems, _ = schema["uniqueItems"].(bool)
+
+	if props, ok := schema["properties"].(map[string]any); ok {
+		node.properties = make(map[string]*jsonSchemaNode, len(props))
+		for name, prop := range props {
+			if node.properties[name], err = c.compile(prop, location+"/properties/"+escapeJSONPointer(name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if props, ok := schema["patternProperties"].(map[string]any); ok {
+		exprs := make([]string, 0, len(props))
+		for expr := range props {
+			exprs = append(exprs, expr)
+		}
+		slices.Sort(exprs)
+
+		for _, expr := range exprs {
+			re, err := pattern("patternProperties", expr)
+			if err != nil {
+				return err
+			}
+
+			prop, err := c.compile(props[expr], location+"/patternProperties/"+escapeJSONPointer(expr))
+			if err != nil {
+				return err
+			}
+
+			node.patternProperties = append(node.patternProperties, jsonSchemaPattern{re: re, node: prop})
+		}
+	}
+
+	if node.additionalProperties, err = sub("additionalProperties"); err != nil {
+		return err
+	}
+	if node.propertyNames, err = sub("propertyNames"); err != nil {
+		return err
+	}
+
+	if required, ok := schema["required"]; ok {
+		if node.required, err = jsonStrings(required); err != nil {
+			return fmt.Errorf("%s/required: %w", location, err)
+		}
+	}
+
+	if deps, ok := schema["dependentRequired"].(map[string]any); ok {
+		node.dependentRequired = make(map[string][]string, len(deps))
+		for name, dep := range deps {
+			if node.dependentRequired[name], err = jsonStrings(dep); err != nil {
+				return fmt.Errorf("%s/dependentRequired: %w", location, err)
+			}
+		}
+	}
+
+	if node.minProps, err = count("minProperties"); err != nil {
+		return err
+	}
+	if node.maxProps, err = count("maxProperties"); err != nil {
+		return err
+	}
+
+	if node.allOf, err = list("allOf"); err != nil {
+		return err
+	}
+	if node.anyOf, err = list("anyOf"); err != nil {
+		return err
+	}
+	if node.oneOf, err = list("oneOf"); err != nil {
+		return err
+	}
+	if node.not, err = sub("not"); err != nil {
+		return err
+	}
+	if node.ifNode, err = sub("if"); err != nil {
+		return err
+	}
+	if node.thenNode, err = sub("then"); err != nil {
+		return err
+	}
+	if node.elseNode, err = sub("else"); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resolve returns the node a local reference such as "#/$defs/name" points
+// to, compiling it if it was not reached while compiling the document.
+func (c *jsonSchemaCompiler) resolve(ref string) (*jsonSchemaNode, error) {
+	if ref != "#" && !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q, only local references are supported", ref)
+	}
+
+	if node, ok := c.nodes[ref]; ok {
+		return node, nil
+	}
+
+	v := c.doc
+
+	for _, token := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+
+		switch current := v.(type) {
+		case map[string]any:
+			var ok bool
+			if v, ok = current[token]; !ok {
+				return nil, fmt.Errorf("unresolved $ref %q", ref)
+			}
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(current) {
+				return nil, fmt.Errorf("unresolved $ref %q", ref)
+			}
+			v = current[i]
+		default:
+			return nil, fmt.Errorf("unresolved $ref %q", ref)
+		}
+	}
+
+	return c.compile(v, ref)
+}
+
+func (n *jsonSchemaNode) valid(v any) bool {
+	var violations []Violation
+	n.validate(v, "", &violations)
+	return len(violations) == 0
+}
+
+func (n *jsonSchemaNode) validate(v any, path string, out *[]Violation) {
+	report := func(rule, format string, args ...any) {
+		*out = append(*out, Violation{Field: path, Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if n.always != nil {
+		if !*n.always {
+			report("false", "no value is allowed")
+		}
+		return
+	}
+
+	if n.refNode != nil {
+		n.refNode.validate(v, path, out)
+	}
+
+	if len(n.types) > 0 && !slices.ContainsFunc(n.types, func(t string) bool { return jsonIsType(v, t) }) {
+		report("type", "must be of type %s, got %s", strings.Join(n.types, " or "), jsonTypeOf(v))
+		return
+	}
+
+	if n.enum != nil && !slices.ContainsFunc(n.enum, func(e any) bool { return jsonEqual(v, e) }) {
+		report("enum", "must be one of %s", jsonText(n.enum))
+	}
+
+	if n.hasConst && !jsonEqual(v, n.constant) {
+		report("const", "must be %s", jsonText(n.constant))
+	}
+
+	switch value := v.(type) {
+	case json.Number:
+		n.validateNumber(jsonRat(value), report)
+	case string:
+		n.validateString(value, report)
+	case []any:
+		n.validateArray(value, path, out, report)
+	case map[string]any:
+		n.validateObject(value, path, out, report)
+	}
+
+	for _, node := range n.allOf {
+		node.validate(v, path, out)
+	}
+
+	if n.anyOf != nil && !slices.ContainsFunc(n.anyOf, func(node *jsonSchemaNode) bool { return node.valid(v) }) {
+		report("anyOf", "must match at least one schema")
+	}
+
+	if n.oneOf != nil {
+		matches := 0
+		for _, node := range n.oneOf {
+			if node.valid(v) {
+				matches++
+			}
+		}
+		if matches != 1 {
+			report("oneOf", "must match exactly one schema, matched %d", matches)
+		}
+	}
+
+	if n.not != nil && n.not.valid(v) {
+		report("not", "must not match the schema")
+	}
+
+	if n.ifNode != nil {
+		if n.ifNode.valid(v) {
+			if n.thenNode != nil {
+				n.thenNode.validate(v, path, out)
+			}
+		} else if n.elseNode != nil {
+			n.elseNode.validate(v, path, out)
+		}
+	}
+}
+
+func (n *jsonSchemaNode) validateNumber(x *big.Rat, report func(rule, format string, args ...any)) {
+	if n.minimum != nil && x.Cmp(n.minimum) < 0 {
+		report("minimum", "must be >= %s", n.minimum.RatString())
+	}
+	if n.maximum != nil && x.Cmp(n.maximum) > 0 {
+		report("maximum", "must be <= %s", n.maximum.RatString())
+	}
+	if n.exclusiveMinimum != nil && x.Cmp(n.exclusiveMinimum) <= 0 {
+		report("exclusiveMinimum", "must be > %s", n.exclusiveMinimum.RatString())
+	}
+	if n.exclusiveMaximum != nil && x.Cmp(n.exclusiveMaximum) >= 0 {
+		report("exclusiveMaximum", "must be < %s", n.exclusiveMaximum.RatString())
+	}
+	if n.multipleOf != nil && !new(big.Rat).Quo(x, n.multipleOf).IsInt() {
+		report("multipleOf", "must be a multiple of %s", n.multipleOf.RatString())
+	}
+}
+
+func (n *jsonSchemaNode) validateString(s string, report func(rule, format string, args ...any)) {
+	length := utf8.RuneCountInString(s)
+
+	if n.minLength != nil && length < *n.minLength {
+		report("minLength", "must be at least %d characters long", *n.minLength)
+	}
+	if n.maxLength != nil && length > *n.maxLength {
+		report("maxLength", "must be at most %d characters long", *n.maxLength)
+	}
+	if n.pattern != nil && !n.pattern.MatchString(s) {
+		report("pattern", "must match %q", n.pattern.String())
+	}
+}
+
+func (n *jsonSchemaNode) validateArray(
+	items []any,
+	path string,
+	out *[]Violation,
+	report func(rule, format string, args ...any),
+) {
+	if n.minItems != nil && len(items) < *n.minItems {
+		report("minItems", "must have at least %d items", *n.minItems)
+	}
+	if n.maxItems != nil && len(items) > *n.maxItems {
+		report("maxItems", "must have at most %d items", *n.maxItems)
+	}
+
+	if n.uniqueItems {
+		for i := 1; i < len(items); i++ {
+			if slices.ContainsFunc(items[:i], func(prev any) bool { return jsonEqual(prev, items[i]) }) {
+				report("uniqueItems", "must not contain duplicates, item %d is repeated", i)
+				break
+			}
+		}
+	}
+
+	for i, item := range items {
+		itemPath := path + "/" + strconv.Itoa(i)
+
+		switch {
+		case i < len(n.prefixItems):
+			n.prefixItems[i].validate(item, itemPath, out)
+		case n.items != nil:
+			n.items.validate(item, itemPath, out)
+		}
+	}
+
+	if n.contains != nil {
+		matches := 0
+		for _, item := range items {
+			if n.contains.valid(item) {
+				matches++
+			}
+		}
+
+		minContains := 1
+		if n.minContains != nil {
+			minContains = *n.minContains
+		}
+
+		if matches < minContains {
+			report("contains", "must contain at least %d matching items, found %d", minContains, matches)
+		}
+		if n.maxContains != nil && matches > *n.maxContains {
+			report("maxContains", "must contain at most %d matching items, found %d", *n.maxContains, matches)
+		}
+	}
+}
+
+func (n *jsonSchemaNode) validateObject(
+	obj map[string]any,
+	path string,
+	out *[]Violation,
+	report func(rule, format string, args ...any),
+) {
+	if n.minProps != nil && len(obj) < *n.minProps {
+		report("minProperties", "must have at least %d properties", *n.minProps)
+	}
+	if n.maxProps != nil && len(obj) > *n.maxProps {
+		report("maxProperties", "must have at most %d properties", *n.maxProps)
+	}
+
+	for _, name := range n.required {
+		if _, ok := obj[name]; !ok {
+			*out = append(*out, Violation{
+				Field:   path + "/" + escapeJSONPointer(name),
+				Rule:    "required",
+				Message: "is required",
+			})
+		}
+	}
+
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, dep := range n.dependentRequired[name] {
+			if _, ok := obj[dep]; !ok {
+				*out = append(*out, Violation{
+					Field:   path + "/" + escapeJSONPointer(dep),
+					Rule:    "dependentRequired",
+					Message: fmt.Sprintf("is required when %s is present", name),
+				})
+			}
+		}
+	}
+
+	for _, name := range names {
+		value := obj[name]
+		propPath := path + "/" + escapeJSONPointer(name)
+
+		if n.propertyNames != nil && !n.propertyNames.valid(name) {
+			*out = append(*out, Violation{Field: propPath, Rule: "propertyNames", Message: "invalid property name"})
+		}
+
+		matched := false
+
+		if prop, ok := n.properties[name]; ok {
+			matched = true
+			prop.validate(value, propPath, out)
+		}
+
+		for _, p := range n.patternProperties {
+			if p.re.MatchString(name) {
+				matched = true
+				p.node.validate(value, propPath, out)
+			}
+		}
+
+		if !matched && n.additionalProperties != nil {
+			if a := n.additionalProperties.always; a != nil && !*a {
+				*out = append(*out, Violation{Field: propPath, Rule: "additionalProperties", Message: "is not allowed"})
+				continue
+			}
+			n.additionalProperties.validate(value, propPath, out)
+		}
+	}
+}
+
+func jsonIsType(v any, t string) bool {
+	switch t {
+	case "integer":
+		n, ok := v.(json.Number)
+		return ok && jsonRat(n).IsInt()
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	default:
+		return jsonTypeOf(v) == t
+	}
+}
+
+func jsonTypeOf(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case json.Number:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// jsonEqual compares decoded JSON values, numbers by their mathematical
+// value so that 1 and 1.0 are equal.
+func jsonEqual(a, b any) bool {
+	switch x := a.(type) {
+	case json.Number:
+		y, ok := b.(json.Number)
+		return ok && jsonRat(x).Cmp(jsonRat(y)) == 0
+	case []any:
+		y, ok := b.([]any)
+		return ok && slices.EqualFunc(x, y, jsonEqual)
+	case map[string]any:
+		y, ok := b.(map[string]any)
+		if !ok || len(x) != len(y) {
+			return false
+		}
+		for k, v := range x {
+			w, ok := y[k]
+			if !ok || !jsonEqual(v, w) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
+
+func jsonRat(n json.Number) *big.Rat {
+	r, ok := new(big.Rat).SetString(n.String())
+	if !ok {
+		return new(big.Rat)
+	}
+	return r
+}
+
+func jsonStrings(v any) ([]string, error) {
+	items, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("must be an array of strings")
+	}
+
+	result := make([]string, len(items))
+	for i, item := range items {
+		if result[i], ok = item.(string); !ok {
+			return nil, fmt.Errorf("must be an array of strings")
+		}
+	}
+	return result, nil
+}
+
+func jsonText(v any) string {
+	data, _ := json.Marshal(v)
+	return string(data)
+}
+
+func escapeJSONPointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+var _ Validator[json.RawMessage] = new(JSONSchema)
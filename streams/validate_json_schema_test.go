@@ -0,0 +1,223 @@
+package streams
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema := MustJSONSchema([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["id", "email"],
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"email": {"type": "string", "pattern": "^[^@]+@[^@]+$"},
+			"name": {"type": "string", "minLength": 2, "maxLength": 5},
+			"status": {"enum": ["active", "banned"]},
+			"price": {"type": "number", "exclusiveMinimum": 0, "multipleOf": 0.01},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true, "maxItems": 3},
+			"point": {"type": "array", "prefixItems": [{"type": "number"}, {"type": "number"}], "items": false},
+			"address": {"$ref": "#/$defs/address"},
+			"kind": {"const": "user"},
+			"contact": {"oneOf": [{"required": ["phone"]}, {"required": ["fax"]}]}
+		},
+		"patternProperties": {"^x-": {"type": "string"}},
+		"additionalProperties": false,
+		"dependentRequired": {"name": ["status"]},
+		"$defs": {
+			"address": {
+				"type": "object",
+				"required": ["city"],
+				"properties": {"city": {"type": "string"}, "parent": {"$ref": "#/$defs/address"}}
+			}
+		}
+	}`))
+
+	tests := []struct {
+		name     string
+		doc      string
+		expected []Violation
+	}{
+		{
+			name: "Valid",
+			doc: `{"id": 1, "email": "a@b.c", "name": "ada", "status": "active", "price": 9.99,
+				"tags": ["a", "b"], "point": [1.5, 2], "address": {"city": "Madrid", "parent": {"city": "Spain"}},
+				"kind": "user", "contact": {"phone": "1"}, "x-trace": "abc"}`,
+		},
+		{
+			name:     "Invalid JSON",
+			doc:      `{"id": `,
+			expected: []Violation{{Rule: "json", Message: "unexpected EOF"}},
+		},
+		{
+			name:     "Wrong root type",
+			doc:      `[]`,
+			expected: []Violation{{Rule: "type", Message: "must be of type object, got array"}},
+		},
+		{
+			name: "Missing required",
+			doc:  `{}`,
+			expected: []Violation{
+				{Field: "/id", Rule: "required", Message: "is required"},
+				{Field: "/email", Rule: "required", Message: "is required"},
+			},
+		},
+		{
+			name: "Property assertions",
+			doc: `{"id": 1.5, "email": "nope", "name": "a", "status": "gone", "price": 0.001,
+				"tags": ["a", "a"], "point": [1, 2, 3], "kind": "admin", "x-trace": 1, "extra": true}`,
+			expected: []Violation{
+				{Field: "/email", Rule: "pattern", Message: `must match "^[^@]+@[^@]+$"`},
+				{Field: "/extra", Rule: "additionalProperties", Message: "is not allowed"},
+				{Field: "/id", Rule: "type", Message: "must be of type integer, got number"},
+				{Field: "/kind", Rule: "const", Message: `must be "user"`},
+				{Field: "/name", Rule: "minLength", Message: "must be at least 2 characters long"},
+				{Field: "/point/2", Rule: "false", Message: "no value is allowed"},
+				{Field: "/price", Rule: "multipleOf", Message: "must be a multiple of 1/100"},
+				{Field: "/status", Rule: "enum", Message: `must be one of ["active","banned"]`},
+				{Field: "/tags", Rule: "uniqueItems", Message: "must not contain duplicates, item 1 is repeated"},
+				{Field: "/x-trace", Rule: "type", Message: "must be of type string, got number"},
+			},
+		},
+		{
+			name: "Nested references and combinators",
+			doc: `{"id": 1, "email": "a@b", "name": "bob", "address": {"parent": {}},
+				"contact": {"phone": "1", "fax": "2"}}`,
+			expected: []Violation{
+				{Field: "/status", Rule: "dependentRequired", Message: "is required when name is present"},
+				{Field: "/address/city", Rule: "required", Message: "is required"},
+				{Field: "/address/parent/city", Rule: "required", Message: "is required"},
+				{Field: "/contact", Rule: "oneOf", Message: "must match exactly one schema, matched 2"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, schema.Validate(json.RawMessage(tt.doc)))
+		})
+	}
+}
+
+func TestJSONSchemaKeywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		valid  []string
+		bad    []string
+	}{
+		{"Boolean true", `true`, []string{`1`, `null`}, nil},
+		{"Boolean false", `false`, nil, []string{`1`}},
+		{"Type list", `{"type": ["string", "null"]}`, []string{`"a"`, `null`}, []string{`1`}},
+		{"Integer accepts 1.0", `{"type": "integer"}`, []string{`1`, `1.0`}, []string{`1.5`}},
+		{"Bounds", `{"minimum": 1, "maximum": 3}`, []string{`1`, `3`, `"x"`}, []string{`0`, `4`}},
+		{"Exclusive bounds", `{"exclusiveMinimum": 1, "exclusiveMaximum": 3}`, []string{`2`}, []string{`1`, `3`}},
+		{"Const compares numbers", `{"const": {"a": [1]}}`, []string{`{"a": [1.0]}`}, []string{`{"a": [2]}`}},
+		{"AnyOf", `{"anyOf": [{"type": "string"}, {"minimum": 10}]}`, []string{`"a"`, `11`}, []string{`5`}},
+		{"AllOf", `{"allOf": [{"minimum": 1}, {"maximum": 2}]}`, []string{`1`}, []string{`3`}},
+		{"Not", `{"not": {"type": "null"}}`, []string{`1`}, []string{`null`}},
+		{
+			"If then else",
+			`{"if": {"minimum": 10}, "then": {"multipleOf": 10}, "else": {"maximum": 5}}`,
+			[]string{`20`, `3`},
+			[]string{`15`, `7`},
+		},
+		{
+			"Contains",
+			`{"contains": {"type": "string"}, "minContains": 2, "maxContains": 3}`,
+			[]string{`["a", "b", 1]`},
+			[]string{`["a", 1]`, `["a", "b", "c", "d"]`},
+		},
+		{"Items", `{"minItems": 1, "items": {"type": "integer"}}`, []string{`[1, 2]`}, []string{`[]`, `[1, "a"]`}},
+		{"Properties count", `{"minProperties": 1, "maxProperties": 1}`, []string{`{"a": 1}`}, []string{`{}`, `{"a": 1, "b": 2}`}},
+		{"Property names", `{"propertyNames": {"maxLength": 2}}`, []string{`{"ab": 1}`}, []string{`{"abc": 1}`}},
+		{
+			"Additional properties schema",
+			`{"properties": {"a": true}, "additionalProperties": {"type": "integer"}}`,
+			[]string{`{"a": "x", "b": 1}`},
+			[]string{`{"b": "x"}`},
+		},
+		{"Unicode length", `{"maxLength": 2}`, []string{`"ñu"`}, []string{`"ñuñ"`}},
+		{"Format is an annotation", `{"format": "email"}`, []string{`"nope"`}, nil},
+		{"Root reference", `{"items": {"$ref": "#"}, "type": "array"}`, []string{`[[], [[]]]`}, []string{`[1]`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema, err := NewJSONSchema([]byte(tt.schema))
+			require.NoError(t, err)
+
+			for _, doc := range tt.valid {
+				assert.Empty(t, schema.Validate(json.RawMessage(doc)), doc)
+			}
+			for _, doc := range tt.bad {
+				assert.NotEmpty(t, schema.Validate(json.RawMessage(doc)), doc)
+			}
+		})
+	}
+}
+
+func TestNewJSONSchemaRootID(t *testing.T) {
+	schema, err := NewJSONSchema([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id": "https://example.com/order",
+		"title": "Order",
+		"type": "object",
+		"required": ["id"]
+	}`))
+	require.NoError(t, err)
+
+	assert.Empty(t, schema.Validate(json.RawMessage(`{"id": 1}`)))
+	assert.Len(t, schema.Validate(json.RawMessage(`{}`)), 1)
+}
+
+func TestNewJSONSchemaErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		err    string
+	}{
+		{"Invalid JSON", `{`, "json schema: unexpected EOF"},
+		{"Not a schema", `1`, "json schema: #: schema must be an object or a boolean"},
+		{"Bad type", `{"type": 1}`, "json schema: #/type: must be a string or an array of strings"},
+		{"Bad pattern", `{"pattern": "("}`, "json schema: #/pattern: error parsing regexp"},
+		{"Bad count", `{"minLength": -1}`, "json schema: #/minLength: must be a non-negative integer"},
+		{"Bad required", `{"required": [1]}`, "json schema: #/required: must be an array of strings"},
+		{"Remote ref", `{"$ref": "https://example.com/schema"}`, `json schema: unsupported $ref "https://example.com/schema"`},
+		{"Missing ref", `{"$ref": "#/$defs/missing"}`, `json schema: unresolved $ref "#/$defs/missing"`},
+		{
+			"Unevaluated properties",
+			`{"properties": {"a": {}}, "unevaluatedProperties": false}`,
+			`json schema: #: unsupported keyword "unevaluatedProperties"`,
+		},
+		{
+			"Nested unevaluated items",
+			`{"properties": {"tags": {"unevaluatedItems": false}}}`,
+			`json schema: #/properties/tags: unsupported keyword "unevaluatedItems"`,
+		},
+		{
+			"Dependent schemas",
+			`{"dependentSchemas": {"a": {"required": ["b"]}}}`,
+			`json schema: #: unsupported keyword "dependentSchemas"`,
+		},
+		{"Anchor", `{"$defs": {"a": {"$anchor": "a"}}}`, `json schema: #/$defs/a: unsupported keyword "$anchor"`},
+		{"Dynamic ref", `{"$dynamicRef": "#node"}`, `json schema: #: unsupported keyword "$dynamicRef"`},
+		{"Draft 7 dependencies", `{"dependencies": {"a": ["b"]}}`, `json schema: #: unsupported keyword "dependencies"`},
+		{
+			"Nested id",
+			`{"$defs": {"a": {"$id": "https://example.com/a"}}}`,
+			`json schema: #/$defs/a: unsupported keyword "$id" outside the root schema`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJSONSchema([]byte(tt.schema))
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,255 @@
+package streams
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+type (
+	// StructValidator validates structs by the rules in their `validate`
+	// tags.
+	StructValidator[T any] struct {
+		fields []structRuleField
+	}
+
+	structRuleField struct {
+		name   string
+		index  []int
+		rules  []structRule
+		nested []structRuleField
+	}
+
+	structRule struct {
+		name  string
+		check func(v reflect.Value) string
+	}
+)
+
+// NewStructValidator creates a new StructValidator for T, a struct whose
+// fields declare comma separated rules in a `validate` tag, such as
+// `validate:"required,min=1"`. The supported rules are:
+//
+//   - required: the value must not be zero, nil or None
+//   - min=n, max=n: bounds for numbers, or for the length of strings, in
+//     characters, slices and maps
+//   - len=n: exact length of strings, slices and maps
+//   - oneof=a b c: the value, as formatted by fmt, must be one of the space
+//     separated options
+//   - email: the value must be an email address
+//
+// Rules other than required are skipped for nil pointers and None options.
+// Nested structs are validated too, and their fields reported with a dotted
+// path such as "Address.City".
+func NewStructValidator[T any]() (*StructValidator[T], error) {
+	t := reflect.TypeFor[T]()
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("validate: %s is not a struct", t)
+	}
+
+	fields, err := structRuleFields(t, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return &StructValidator[T]{fields: fields}, nil
+}
+
+// MustStructValidator is like NewStructValidator but panics on error.
+func MustStructValidator[T any]() *StructValidator[T] {
+	v, err := NewStructValidator[T]()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+func (s *StructValidator[T]) Validate(item T) []Violation {
+	var violations []Violation
+	validateStructFields(reflect.ValueOf(item), s.fields, &violations)
+	return violations
+}
+
+func validateStructFields(v reflect.Value, fields []structRuleField, out *[]Violation) {
+	for _, f := range fields {
+		value := v.FieldByIndex(f.index)
+
+		present := true
+		switch {
+		case value.Kind() == reflect.Pointer:
+			present = !value.IsNil()
+		case value.Type().Implements(optionalType):
+			present = !value.Interface().(csvOptional).IsNone()
+		}
+
+		for _, rule := range f.rules {
+			if rule.name == "required" {
+				if !present || value.IsZero() {
+					*out = append(*out, Violation{Field: f.name, Rule: rule.name, Message: "is required"})
+				}
+				continue
+			}
+
+			if !present {
+				continue
+			}
+
+			if message := rule.check(structRuleValue(value)); message != "" {
+				*out = append(*out, Violation{Field: f.name, Rule: rule.name, Message: message})
+			}
+		}
+
+		if f.nested != nil && present {
+			validateStructFields(reflect.Indirect(value), f.nested, out)
+		}
+	}
+}
+
+// structRuleValue returns the value rules apply to, following pointers and
+// unwrapping options.
+func structRuleValue(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Pointer {
+		return v.Elem()
+	}
+
+	if v.Type().Implements(optionalType) {
+		if unwrap := v.MethodByName("Unwrap"); unwrap.IsValid() {
+			return unwrap.Call(nil)[0]
+		}
+	}
+
+	return v
+}
+
+func structRuleFields(t reflect.Type, prefix string) ([]structRuleField, error) {
+	var fields []structRuleField
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("validate")
+		if tag == "-" {
+			continue
+		}
+
+		field := structRuleField{name: prefix + f.Name, index: f.Index}
+
+		if tag != "" {
+			for _, spec := range strings.Split(tag, ",") {
+				rule, err := newStructRule(strings.TrimSpace(spec))
+				if err != nil {
+					return nil, fmt.Errorf("validate: field %s: %w", field.name, err)
+				}
+				field.rules = append(field.rules, rule)
+			}
+		}
+
+		if csvIsNested(f.Type) {
+			nested, err := structRuleFields(csvDeref(f.Type), field.name+".")
+			if err != nil {
+				return nil, err
+			}
+			field.nested = nested
+		}
+
+		if field.rules != nil || field.nested != nil {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields, nil
+}
+
+func newStructRule(spec string) (structRule, error) {
+	name, arg, _ := strings.Cut(spec, "=")
+	rule := structRule{name: name}
+
+	switch name {
+	case "required":
+	case "min", "max", "len":
+		bound, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return rule, fmt.Errorf("invalid %s rule %q", name, spec)
+		}
+		rule.check = structBoundCheck(name, bound)
+	case "oneof":
+		options := strings.Fields(arg)
+		if len(options) == 0 {
+			return rule, fmt.Errorf("invalid oneof rule %q", spec)
+		}
+		rule.check = func(v reflect.Value) string {
+			s := fmt.Sprint(v.Interface())
+			for _, option := range options {
+				if s == option {
+					return ""
+				}
+			}
+			return fmt.Sprintf("must be one of %s", strings.Join(options, ", "))
+		}
+	case "email":
+		rule.check = func(v reflect.Value) string {
+			if v.Kind() == reflect.String {
+				addr, err := mail.ParseAddress(v.String())
+				if err == nil && addr.Address == v.String() {
+					return ""
+				}
+			}
+			return "must be an email address"
+		}
+	default:
+		return rule, fmt.Errorf("unknown rule %q", spec)
+	}
+
+	return rule, nil
+}
+
+// structBoundCheck compares numbers by value and everything else with a
+// length by length.
+func structBoundCheck(name string, bound float64) func(reflect.Value) string {
+	return func(v reflect.Value) string {
+		var (
+			x    float64
+			unit string
+		)
+
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			x = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			x = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			x = v.Float()
+		case reflect.String:
+			x, unit = float64(utf8.RuneCountInString(v.String())), " characters"
+		case reflect.Slice, reflect.Map, reflect.Array:
+			x, unit = float64(v.Len()), " items"
+		default:
+			return fmt.Sprintf("%s does not apply to %s", name, v.Type())
+		}
+
+		limit := strconv.FormatFloat(bound, 'f', -1, 64)
+
+		switch {
+		case name == "min" && x < bound:
+			if unit != "" {
+				return fmt.Sprintf("must be at least %s%s long", limit, unit)
+			}
+			return "must be >= " + limit
+		case name == "max" && x > bound:
+			if unit != "" {
+				return fmt.Sprintf("must be at most %s%s long", limit, unit)
+			}
+			return "must be <= " + limit
+		case name == "len" && x != bound:
+			return fmt.Sprintf("must be exactly %s%s long", limit, unit)
+		}
+
+		return ""
+	}
+}
@@ -0,0 +1,122 @@
+package streams
+
+import (
+	"testing"
+
+	"github.com/sonirico/vago/fp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type validateAddress struct {
+	City string `validate:"required"`
+	Zip  string `validate:"len=5"`
+}
+
+type validateCustomer struct {
+	Name     string          `validate:"required,min=2,max=10"`
+	Email    string          `validate:"email"`
+	Age      int             `validate:"min=18,max=130"`
+	Tier     string          `validate:"oneof=free pro"`
+	Tags     []string        `validate:"max=2"`
+	Nickname *string         `validate:"min=3"`
+	Score    fp.Option[int]  `validate:"required,max=100"`
+	Address  validateAddress `validate:"required"`
+	Billing  *validateAddress
+	internal string `validate:"required"`
+}
+
+func TestStructValidator(t *testing.T) {
+	v := MustStructValidator[validateCustomer]()
+
+	short := "ab"
+
+	tests := []struct {
+		name     string
+		item     validateCustomer
+		expected []Violation
+	}{
+		{
+			name: "Valid",
+			item: validateCustomer{
+				Name:    "Ada",
+				Email:   "ada@example.com",
+				Age:     36,
+				Tier:    "pro",
+				Score:   fp.Some(0),
+				Address: validateAddress{City: "London", Zip: "12345"},
+			},
+		},
+		{
+			name: "Every rule fails",
+			item: validateCustomer{
+				Name:     "A",
+				Email:    "Ada <ada@example.com>",
+				Age:      12,
+				Tier:     "gold",
+				Tags:     []string{"a", "b", "c"},
+				Nickname: &short,
+				Billing:  &validateAddress{Zip: "1"},
+			},
+			expected: []Violation{
+				{Field: "Name", Rule: "min", Message: "must be at least 2 characters long"},
+				{Field: "Email", Rule: "email", Message: "must be an email address"},
+				{Field: "Age", Rule: "min", Message: "must be >= 18"},
+				{Field: "Tier", Rule: "oneof", Message: "must be one of free, pro"},
+				{Field: "Tags", Rule: "max", Message: "must be at most 2 items long"},
+				{Field: "Nickname", Rule: "min", Message: "must be at least 3 characters long"},
+				{Field: "Score", Rule: "required", Message: "is required"},
+				{Field: "Address", Rule: "required", Message: "is required"},
+				{Field: "Address.City", Rule: "required", Message: "is required"},
+				{Field: "Address.Zip", Rule: "len", Message: "must be exactly 5 characters long"},
+				{Field: "Billing.City", Rule: "required", Message: "is required"},
+				{Field: "Billing.Zip", Rule: "len", Message: "must be exactly 5 characters long"},
+			},
+		},
+		{
+			name: "Option values are unwrapped",
+			item: validateCustomer{
+				Name:    "Ada",
+				Email:   "ada@example.com",
+				Age:     36,
+				Tier:    "free",
+				Score:   fp.Some(101),
+				Address: validateAddress{City: "London", Zip: "12345"},
+			},
+			expected: []Violation{
+				{Field: "Score", Rule: "max", Message: "must be <= 100"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, v.Validate(tt.item))
+		})
+	}
+}
+
+func TestNewStructValidatorErrors(t *testing.T) {
+	t.Run("Unknown rule", func(t *testing.T) {
+		type bad struct {
+			Name string `validate:"required,shiny"`
+		}
+
+		_, err := NewStructValidator[bad]()
+		assert.EqualError(t, err, `validate: field Name: unknown rule "shiny"`)
+	})
+
+	t.Run("Invalid bound", func(t *testing.T) {
+		type bad struct {
+			Name string `validate:"min=x"`
+		}
+
+		_, err := NewStructValidator[bad]()
+		assert.EqualError(t, err, `validate: field Name: invalid min rule "min=x"`)
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		_, err := NewStructValidator[int]()
+		require.Error(t, err)
+	})
+}